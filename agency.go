@@ -0,0 +1,135 @@
+package yandex_direct_sdk
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mg-realcom/yandex-direct-sdk/agencyclients"
+	"github.com/mg-realcom/yandex-direct-sdk/clients"
+	"github.com/mg-realcom/yandex-direct-sdk/common"
+)
+
+// agencyClientsPageLimit максимальный размер страницы сервиса AgencyClients.
+const agencyClientsPageLimit = 10_000
+
+// DefaultAgencyClientFields параметры клиентов агентства, запрашиваемые по умолчанию.
+func DefaultAgencyClientFields() []clients.FieldName {
+	return []clients.FieldName{
+		clients.FieldClientID,
+		clients.FieldLogin,
+		clients.FieldClientInfo,
+		clients.FieldArchived,
+		clients.FieldCurrency,
+	}
+}
+
+// GetAgencyClients возвращает всех клиентов агентства, подходящих под критерий, запрашивая страницы до конца выборки.
+func (c *Client) GetAgencyClients(ctx context.Context, criteria agencyclients.SelectionCriteria, fields []clients.FieldName) ([]clients.ClientGetItem, error) {
+	if len(fields) == 0 {
+		fields = DefaultAgencyClientFields()
+	}
+
+	params := agencyclients.GetParams{
+		SelectionCriteria: criteria,
+		FieldNames:        fields,
+		Page:              &common.Page{Limit: agencyClientsPageLimit},
+	}
+
+	var items []clients.ClientGetItem
+
+	for {
+		var result agencyclients.GetResult
+		if err := c.Call(ctx, "agencyclients", "get", params, &result); err != nil {
+			return items, fmt.Errorf("agencyclients.get: %w", err)
+		}
+
+		items = append(items, result.Clients...)
+
+		if result.LimitedBy == nil {
+			return items, nil
+		}
+
+		params.Page.Offset = *result.LimitedBy
+	}
+}
+
+// AddAgencyClient создает клиента агентства.
+func (c *Client) AddAgencyClient(ctx context.Context, params agencyclients.AddParams) (agencyclients.AddResult, error) {
+	var result agencyclients.AddResult
+	if err := c.Call(ctx, "agencyclients", "add", params, &result); err != nil {
+		return result, fmt.Errorf("agencyclients.add: %w", err)
+	}
+
+	return result, nil
+}
+
+// UpdateAgencyClients изменяет параметры клиентов агентства.
+func (c *Client) UpdateAgencyClients(ctx context.Context, items []agencyclients.ClientUpdateItem) ([]common.ActionResult, error) {
+	var result agencyclients.UpdateResult
+	if err := c.Call(ctx, "agencyclients", "update", agencyclients.UpdateParams{Clients: items}, &result); err != nil {
+		return nil, fmt.Errorf("agencyclients.update: %w", err)
+	}
+
+	return result.UpdateResults, nil
+}
+
+// Agency работает с клиентами агентства. Клиенты, порожденные агентством, используют общий токен,
+// транспорт, ограничитель частоты запросов и учет баллов.
+type Agency struct {
+	client *Client
+}
+
+// NewAgency создает обертку над клиентом с токеном агентства. Client.Login должен быть логином агентства или пустым.
+func NewAgency(c *Client) *Agency {
+	return &Agency{client: c}
+}
+
+// Client возвращает клиента агентства.
+func (a *Agency) Client() *Client {
+	return a.client
+}
+
+// Clients возвращает клиентов агентства. Nil archived — все клиенты, иначе только архивные или активные.
+func (a *Agency) Clients(ctx context.Context, archived *common.YesNo, fields ...clients.FieldName) ([]clients.ClientGetItem, error) {
+	return a.client.GetAgencyClients(ctx, agencyclients.SelectionCriteria{Archived: archived}, fields)
+}
+
+// For возвращает клиента, выполняющего запросы от имени клиента агентства login.
+func (a *Agency) For(login string) *Client {
+	return a.client.WithLogin(login)
+}
+
+// Logins возвращает логины клиентов агентства.
+func (a *Agency) Logins(ctx context.Context, archived *common.YesNo) ([]string, error) {
+	items, err := a.Clients(ctx, archived, clients.FieldLogin)
+	if err != nil {
+		return nil, err
+	}
+
+	logins := make([]string, 0, len(items))
+	for _, item := range items {
+		logins = append(logins, item.Login)
+	}
+
+	return logins, nil
+}
+
+// ForEach последовательно вызывает fn для каждого клиента агентства. Обход прекращается при первой ошибке fn.
+func (a *Agency) ForEach(ctx context.Context, archived *common.YesNo, fn func(ctx context.Context, client *Client, item clients.ClientGetItem) error) error {
+	items, err := a.Clients(ctx, archived)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if err := ctx.Err(); err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		if err := fn(ctx, a.For(item.Login), item); err != nil {
+			return fmt.Errorf("%s: %w", item.Login, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package yandex_direct_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mg-realcom/yandex-direct-sdk/agencyclients"
+	"github.com/mg-realcom/yandex-direct-sdk/clients"
+)
+
+// agencyClientsServer отдает клиентов агентства страницами по pageSize, как Директ при превышении лимита выборки.
+type agencyClientsServer struct {
+	logins   []string
+	pageSize int
+	offsets  []int
+}
+
+func (s *agencyClientsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Params agencyclients.GetParams `json:"params"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	offset := req.Params.Page.Offset
+	s.offsets = append(s.offsets, offset)
+
+	end := offset + s.pageSize
+	if end > len(s.logins) {
+		end = len(s.logins)
+	}
+
+	result := agencyclients.GetResult{}
+	for _, login := range s.logins[offset:end] {
+		result.Clients = append(result.Clients, clients.ClientGetItem{Login: login})
+	}
+
+	if end < len(s.logins) {
+		result.LimitedBy = &end
+	}
+
+	body, _ := json.Marshal(result)
+	fmt.Fprintf(w, `{"result":%s}`, body)
+}
+
+func TestGetAgencyClientsPages(t *testing.T) {
+	tests := []struct {
+		name    string
+		logins  []string
+		offsets []int
+	}{
+		{name: "empty", offsets: []int{0}},
+		{name: "one page", logins: []string{"a", "b"}, offsets: []int{0}},
+		{name: "limited", logins: []string{"a", "b", "c", "d", "e"}, offsets: []int{0, 2, 4}},
+		{name: "exact pages", logins: []string{"a", "b", "c", "d"}, offsets: []int{0, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &agencyClientsServer{logins: tt.logins, pageSize: 2}
+			token := "token"
+			c := newOAuthClient(t, srv)
+			c.Token = &token
+
+			logins, err := NewAgency(c).Logins(context.Background(), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if strings.Join(logins, ",") != strings.Join(tt.logins, ",") {
+				t.Errorf("logins = %q; want %q", logins, tt.logins)
+			}
+
+			if fmt.Sprint(srv.offsets) != fmt.Sprint(tt.offsets) {
+				t.Errorf("offsets = %v; want %v", srv.offsets, tt.offsets)
+			}
+		})
+	}
+}
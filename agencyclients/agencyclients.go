@@ -0,0 +1,60 @@
+package agencyclients
+
+import (
+	"github.com/mg-realcom/yandex-direct-sdk/clients"
+	"github.com/mg-realcom/yandex-direct-sdk/common"
+)
+
+type GetParams struct {
+	SelectionCriteria SelectionCriteria   `json:"SelectionCriteria"` // Критерий отбора клиентов.
+	FieldNames        []clients.FieldName `json:"FieldNames"`        // Имена параметров, которые требуется получить.
+	Page              *common.Page        `json:"Page,omitempty"`    // Страница при постраничной выборке данных.
+}
+
+type SelectionCriteria struct {
+	Logins   []string      `json:"Logins,omitempty"`   // Отбирать клиентов с указанными логинами. Не более 10 000 элементов в массиве.
+	Archived *common.YesNo `json:"Archived,omitempty"` // Отбирать архивных (YES) или активных (NO) клиентов.
+}
+
+type GetResult struct {
+	Clients   []clients.ClientGetItem `json:"Clients"`
+	LimitedBy *int                    `json:"LimitedBy,omitempty"` // Порядковый номер последнего возвращенного объекта, если получены не все объекты.
+}
+
+type AddParams struct {
+	Login        string                         `json:"Login"`              // Логин создаваемого клиента.
+	FirstName    string                         `json:"FirstName"`          // Имя клиента.
+	LastName     string                         `json:"LastName"`           // Фамилия клиента.
+	Currency     clients.Currency               `json:"Currency"`           // Валюта клиента.
+	Grants       []clients.GrantItem            `json:"Grants,omitempty"`   // Полномочия клиента.
+	Notification clients.Notification           `json:"Notification"`       // Настройки уведомлений.
+	Settings     []clients.ClientSettingAddItem `json:"Settings,omitempty"` // Настройки клиента.
+	TinInfo      *clients.TinInfo               `json:"TinInfo,omitempty"`  // Сведения об ИНН клиента.
+}
+
+type AddResult struct {
+	Login    string                         `json:"Login,omitempty"`
+	Password string                         `json:"Password,omitempty"`
+	Email    string                         `json:"Email,omitempty"`
+	ClientID int64                          `json:"ClientId,omitempty"`
+	Warnings []common.ExceptionNotification `json:"Warnings,omitempty"`
+	Errors   []common.ExceptionNotification `json:"Errors,omitempty"`
+}
+
+type UpdateParams struct {
+	Clients []ClientUpdateItem `json:"Clients"`
+}
+
+type ClientUpdateItem struct {
+	ClientID     int64                             `json:"ClientId"`               // Идентификатор клиента.
+	ClientInfo   string                            `json:"ClientInfo,omitempty"`   // ФИО или название клиента.
+	Notification *clients.NotificationUpdate       `json:"Notification,omitempty"` // Настройки уведомлений.
+	Phone        string                            `json:"Phone,omitempty"`        // Номер телефона клиента.
+	Settings     []clients.ClientSettingUpdateItem `json:"Settings,omitempty"`     // Настройки клиента.
+	Grants       []clients.GrantItem               `json:"Grants,omitempty"`       // Полномочия клиента.
+	TinInfo      *clients.TinInfo                  `json:"TinInfo,omitempty"`      // Сведения об ИНН клиента.
+}
+
+type UpdateResult struct {
+	UpdateResults []common.ActionResult `json:"UpdateResults"`
+}
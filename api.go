@@ -0,0 +1,137 @@
+package yandex_direct_sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"strconv"
+	"time"
+)
+
+// ErrorCode код ошибки API. Сервис Reports возвращает его строкой, остальные сервисы — числом.
+type ErrorCode string
+
+func (e *ErrorCode) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*e = ErrorCode(s)
+
+		return nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("error_code: %w", err)
+	}
+
+	*e = ErrorCode(n.String())
+
+	return nil
+}
+
+// APIError ошибка, возвращенная API Директа.
+type APIError struct {
+	RequestID   string    `json:"request_id"`
+	ErrorCode   ErrorCode `json:"error_code"`
+	ErrorString string    `json:"error_string"`
+	ErrorDetail string    `json:"error_detail"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("код ошибки %s: %s. %s (request_id %s)", e.ErrorCode, e.ErrorString, e.ErrorDetail, e.RequestID)
+}
+
+// Code возвращает код ошибки числом или 0, если код не числовой.
+func (e *APIError) Code() int {
+	code, _ := strconv.Atoi(string(e.ErrorCode))
+
+	return code
+}
+
+type apiRequest struct {
+	Method string      `json:"method"`
+	Params interface{} `json:"params"`
+}
+
+type apiResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *APIError       `json:"error"`
+}
+
+func (c *Client) endpoint(service string) string {
+	return fmt.Sprintf("https://%s/json/v5/%s", c.host, service)
+}
+
+// Call вызывает метод сервиса JSON API Директа от имени Client.Login и разбирает поле result ответа в result.
+func (c *Client) Call(ctx context.Context, service, method string, params, result interface{}) error {
+	return c.call(ctx, c.Login, service, method, params, result)
+}
+
+// call вызывает метод API. Пустой login означает запрос без заголовка Client-Login, от имени владельца токена.
+func (c *Client) call(ctx context.Context, login, service, method string, params, result interface{}) error {
+	body, err := json.Marshal(apiRequest{Method: method, Params: params})
+	if err != nil {
+		return fmt.Errorf("marshal %s.%s: %w", service, method, err)
+	}
+
+	ctx = WithCallInfo(ctx, CallInfo{Service: service, Method: method, Login: login, Attempt: 1, Started: time.Now()})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint(service), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request %s.%s: %w", service, method, err)
+	}
+
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	if err := c.buildHeader(req); err != nil {
+		return err
+	}
+
+	if login == "" {
+		req.Header.Del("Client-Login")
+	}
+
+	reqDump, _ := httputil.DumpRequestOut(req, true)
+
+	resp, err := c.doAuthorized(req)
+	if err != nil {
+		return fmt.Errorf("do request %s.%s: %w", service, method, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response %s.%s: %w", service, method, err)
+	}
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		c.logDumps(reqDump, dumpResponseHead(resp, respBody))
+	}
+
+	var data apiResponse
+	if err := json.Unmarshal(respBody, &data); err != nil {
+		return fmt.Errorf("%s.%s: статус %v: %w", service, method, resp.StatusCode, err)
+	}
+
+	if data.Error != nil {
+		return data.Error
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s.%s: статус код сервера %v", service, method, resp.StatusCode)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(data.Result, result); err != nil {
+		return fmt.Errorf("unmarshal result %s.%s: %w", service, method, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,282 @@
+package yandex_direct_sdk
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// DefaultRedirectURL адрес локального обработчика по умолчанию. Должен быть указан в настройках приложения как Redirect URI.
+const DefaultRedirectURL = "http://127.0.0.1:8765/callback"
+
+const (
+	verifierSize      = 32
+	stateSize         = 16
+	shutdownTimeout   = 5 * time.Second
+	readHeaderTimeout = 10 * time.Second
+)
+
+var (
+	ErrStateMismatch  = errors.New("oauth state mismatch")
+	ErrNoCode         = errors.New("authorization code is missing in callback")
+	ErrRedirectScheme = errors.New("redirect url must use http scheme")
+	ErrRedirectHost   = errors.New("redirect url host must be a loopback address")
+)
+
+// AuthCodeOptions параметры авторизации с кодом подтверждения через локальный обработчик.
+type AuthCodeOptions struct {
+	RedirectURL  string                    // Адрес локального обработчика, по умолчанию DefaultRedirectURL. Без порта выбирается свободный порт.
+	Scopes       []string                  // Запрашиваемые права. Пусто — права, заданные в настройках приложения.
+	LoginHint    string                    // Логин или e-mail, для которого запрашивается токен.
+	ForceConfirm bool                      // Всегда запрашивать у пользователя подтверждение доступа.
+	OpenURL      func(rawURL string) error // Открывает страницу согласия. По умолчанию открывается браузер.
+}
+
+// AuthoriseWithCallback выполняет авторизацию с кодом подтверждения (RFC 6749) с PKCE и проверкой state:
+// запускает локальный обработчик, открывает страницу согласия, обменивает полученный код на токен.
+func (c *Client) AuthoriseWithCallback(ctx context.Context, opts AuthCodeOptions) (Token, error) {
+	if opts.RedirectURL == "" {
+		opts.RedirectURL = DefaultRedirectURL
+	}
+
+	if opts.OpenURL == nil {
+		opts.OpenURL = OpenBrowser
+	}
+
+	redirect, err := url.Parse(opts.RedirectURL)
+	if err != nil {
+		return Token{}, fmt.Errorf("parse redirect url: %w", err)
+	}
+
+	verifier, err := randomString(verifierSize)
+	if err != nil {
+		return Token{}, err
+	}
+
+	state, err := randomString(stateSize)
+	if err != nil {
+		return Token{}, err
+	}
+
+	addr, err := listenAddr(redirect)
+	if err != nil {
+		return Token{}, err
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return Token{}, fmt.Errorf("listen %s: %w", addr, err)
+	}
+
+	if redirect.Port() == "" {
+		// Порт выбран системой, и адрес возврата должен его содержать (RFC 8252, раздел 7.3).
+		_, port, _ := net.SplitHostPort(listener.Addr().String())
+		redirect.Host = net.JoinHostPort(redirect.Hostname(), port)
+		opts.RedirectURL = redirect.String()
+	}
+
+	codes := make(chan callbackResult, 1)
+	server := &http.Server{
+		Handler:           callbackHandler(redirect.Path, state, codes),
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	go func() { _ = server.Serve(listener) }()
+
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		_ = server.Shutdown(shutdownCtx) //nolint:contextcheck
+	}()
+
+	if err := opts.OpenURL(c.AuthCodeURL(state, codeChallenge(verifier), opts)); err != nil {
+		return Token{}, fmt.Errorf("open consent page: %w", err)
+	}
+
+	select {
+	case <-ctx.Done():
+		return Token{}, ctx.Err() //nolint:wrapcheck
+	case res := <-codes:
+		if res.err != nil {
+			return Token{}, res.err
+		}
+
+		return c.ExchangeCode(ctx, res.code, verifier, opts.RedirectURL)
+	}
+}
+
+// AuthCodeURL возвращает адрес страницы согласия для авторизации с кодом подтверждения.
+func (c *Client) AuthCodeURL(state, challenge string, opts AuthCodeOptions) string {
+	param := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {c.App.ID},
+		"redirect_uri":          {opts.RedirectURL},
+		"state":                 {state},
+		"code_challenge":        {challenge},
+		"code_challenge_method": {"S256"},
+	}
+
+	if len(opts.Scopes) > 0 {
+		param.Set("scope", strings.Join(opts.Scopes, " "))
+	}
+
+	if opts.LoginHint != "" {
+		param.Set("login_hint", opts.LoginHint)
+	}
+
+	if opts.ForceConfirm {
+		param.Set("force_confirm", "yes")
+	}
+
+	authURL := url.URL{
+		Scheme:   "https",
+		Host:     oauthHost,
+		Path:     "/authorize",
+		RawQuery: param.Encode(),
+	}
+
+	return authURL.String()
+}
+
+// ExchangeCode обменивает код подтверждения на токен.
+func (c *Client) ExchangeCode(ctx context.Context, code, verifier, redirectURL string) (Token, error) {
+	reqAccessURL := url.URL{
+		Scheme: "https",
+		Host:   oauthHost,
+		Path:   "/token",
+	}
+	param := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"client_id":     {c.App.ID},
+		"client_secret": {c.App.Secret},
+		"code_verifier": {verifier},
+		"redirect_uri":  {redirectURL},
+	}
+
+	return c.requestToken(ctx, reqAccessURL.String(), param)
+}
+
+// listenAddr возвращает адрес локального обработчика. Допускаются только адреса обратной петли
+// (RFC 8252, раздел 7.3), чтобы обработчик не был доступен из сети. Без явного порта используется порт 0:
+// система выбирает свободный порт, и привилегии для порта 80 не нужны.
+func listenAddr(redirect *url.URL) (string, error) {
+	if redirect.Scheme != "http" {
+		return "", fmt.Errorf("%w: %s", ErrRedirectScheme, redirect.Scheme)
+	}
+
+	host := redirect.Hostname()
+	if host != "127.0.0.1" && host != "::1" && !strings.EqualFold(host, "localhost") {
+		return "", fmt.Errorf("%w: %q", ErrRedirectHost, host)
+	}
+
+	port := redirect.Port()
+	if port == "" {
+		port = "0"
+	}
+
+	return net.JoinHostPort(host, port), nil
+}
+
+type callbackResult struct {
+	code string
+	err  error
+}
+
+// callbackHandler принимает запросы только на путь path. Путь сравнивается целиком, а не используется
+// как шаблон ServeMux, поэтому символы шаблонов в адресе возврата ничего не значат.
+func callbackHandler(path, state string, results chan<- callbackResult) http.Handler {
+	if path == "" {
+		path = "/"
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		query := r.URL.Query()
+
+		// Посторонние запросы (без state, с чужим state или без кода и ошибки) не завершают ожидание.
+		if subtle.ConstantTimeCompare([]byte(query.Get("state")), []byte(state)) != 1 {
+			http.Error(w, ErrStateMismatch.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		var res callbackResult
+
+		switch {
+		case query.Get("error") != "":
+			res.err = responseError{Err: query.Get("error"), Msg: query.Get("error_description")}
+		case query.Get("code") == "":
+			http.Error(w, ErrNoCode.Error(), http.StatusBadRequest)
+
+			return
+		default:
+			res.code = query.Get("code")
+		}
+
+		if res.err != nil {
+			http.Error(w, "Авторизация не выполнена: "+res.err.Error(), http.StatusBadRequest)
+		} else {
+			_, _ = fmt.Fprintln(w, "Авторизация выполнена, окно можно закрыть.")
+		}
+
+		select {
+		case results <- res:
+		default:
+		}
+	})
+}
+
+// OpenBrowser открывает адрес в браузере по умолчанию.
+func OpenBrowser(rawURL string) error {
+	var cmd *exec.Cmd
+
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", rawURL)
+	case "darwin":
+		cmd = exec.Command("open", rawURL)
+	default:
+		cmd = exec.Command("xdg-open", rawURL)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start browser: %w", err)
+	}
+
+	go func() { _ = cmd.Wait() }()
+
+	return nil
+}
+
+func randomString(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("random: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
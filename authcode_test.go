@@ -0,0 +1,108 @@
+package yandex_direct_sdk
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestCallbackHandler(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		status  int
+		code    string
+		waiting bool
+	}{
+		{name: "no state", query: "code=abc", status: http.StatusBadRequest, waiting: true},
+		{name: "wrong state", query: "state=other&code=abc", status: http.StatusBadRequest, waiting: true},
+		{name: "no code", query: "state=s1", status: http.StatusBadRequest, waiting: true},
+		{name: "denied", query: "state=s1&error=access_denied", status: http.StatusBadRequest},
+		{name: "code", query: "state=s1&code=abc", status: http.StatusOK, code: "abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := make(chan callbackResult, 1)
+			rec := httptest.NewRecorder()
+
+			callbackHandler("/callback", "s1", results).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/callback?"+tt.query, nil))
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d; want %d", rec.Code, tt.status)
+			}
+
+			select {
+			case res := <-results:
+				if tt.waiting {
+					t.Fatalf("got result %+v; want no result", res)
+				}
+
+				if res.code != tt.code {
+					t.Errorf("code = %q; want %q", res.code, tt.code)
+				}
+			default:
+				if !tt.waiting {
+					t.Fatal("no result")
+				}
+			}
+		})
+	}
+}
+
+func TestCallbackHandlerPath(t *testing.T) {
+	tests := []struct {
+		path   string
+		target string
+		status int
+	}{
+		{path: "/cb/{id}", target: "/cb/%7Bid%7D?state=s1&code=abc", status: http.StatusOK},
+		{path: "/cb/{id}", target: "/cb/other?state=s1&code=abc", status: http.StatusNotFound},
+		{path: "/callback", target: "/callback/extra?state=s1&code=abc", status: http.StatusNotFound},
+		{path: "", target: "/?state=s1&code=abc", status: http.StatusOK},
+		{path: "", target: "/favicon.ico", status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		results := make(chan callbackResult, 1)
+		rec := httptest.NewRecorder()
+
+		callbackHandler(tt.path, "s1", results).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+		if rec.Code != tt.status {
+			t.Errorf("path %q, target %q: status = %d; want %d", tt.path, tt.target, rec.Code, tt.status)
+		}
+	}
+}
+
+func TestListenAddr(t *testing.T) {
+	tests := []struct {
+		rawURL string
+		want   string
+		err    error
+	}{
+		{rawURL: "http://127.0.0.1:8765/callback", want: "127.0.0.1:8765"},
+		{rawURL: "http://localhost/callback", want: "localhost:0"},
+		{rawURL: "http://LocalHost:8765/callback", want: "LocalHost:8765"},
+		{rawURL: "http://[::1]/callback", want: "[::1]:0"},
+		{rawURL: "https://localhost/callback", err: ErrRedirectScheme},
+		{rawURL: "http:///callback", err: ErrRedirectHost},
+		{rawURL: "http://0.0.0.0:8765/callback", err: ErrRedirectHost},
+		{rawURL: "http://[::]:8765/callback", err: ErrRedirectHost},
+		{rawURL: "http://example.com/callback", err: ErrRedirectHost},
+	}
+
+	for _, tt := range tests {
+		u, err := url.Parse(tt.rawURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := listenAddr(u)
+		if !errors.Is(err, tt.err) || got != tt.want {
+			t.Errorf("listenAddr(%q) = %q, %v; want %q, %v", tt.rawURL, got, err, tt.want, tt.err)
+		}
+	}
+}
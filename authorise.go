@@ -1,15 +1,28 @@
 package yandex_direct_sdk
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httputil"
 	"net/url"
+	"strings"
 	"time"
 )
 
+const (
+	oauthHost = "oauth.yandex.ru"
+
+	defaultPollInterval = 5 // Интервал опроса в секундах, если сервер его не вернул.
+	slowDownIncrement   = 5 // Увеличение интервала опроса в секундах по ответу slow_down.
+)
+
+// pollUnit единица интервалов и срока действия кода подтверждения, в которой их возвращает сервер.
+var pollUnit = time.Second //nolint:gochecknoglobals
+
 type AuthByCode struct {
 	DeviceCode      string `json:"device_code"`
 	ExpiresIn       int    `json:"expires_in"`
@@ -27,96 +40,155 @@ func (e responseError) Error() string {
 	return fmt.Sprintf("%s: %s", e.Err, e.Msg)
 }
 
-var ErrAuthorisationPending = responseError{
-	Err: "authorization_pending",
-	Msg: "User has not yet authorized your application",
+// Is сравнивает ошибки OAuth по коду, без учета описания.
+func (e responseError) Is(target error) bool {
+	var t responseError
+
+	return errors.As(target, &t) && t.Err == e.Err
 }
 
+var (
+	ErrAuthorisationPending = responseError{
+		Err: "authorization_pending",
+		Msg: "User has not yet authorized your application",
+	}
+	ErrSlowDown = responseError{
+		Err: "slow_down",
+		Msg: "Polling too frequently",
+	}
+	ErrExpiredToken = responseError{
+		Err: "expired_token",
+		Msg: "Device code has expired",
+	}
+	ErrAccessDenied = responseError{
+		Err: "access_denied",
+		Msg: "User denied access",
+	}
+)
+
 type Token struct {
-	TokenType    string `json:"token_type"`
-	AccessToken  string `json:"access_token"`
-	ExpiresIn    int64  `json:"expires_in"`
-	RefreshToken string `json:"refresh_token"`
-	Scope        string `json:"scope"`
+	TokenType    string    `json:"token_type"`
+	AccessToken  string    `json:"access_token"`
+	ExpiresIn    int64     `json:"expires_in"`
+	RefreshToken string    `json:"refresh_token"`
+	Scope        string    `json:"scope"`
+	Expiry       time.Time `json:"expiry"` // Момент истечения, вычисляется по ExpiresIn при получении токена.
+}
+
+type AuthStage string
+
+const (
+	AuthStageUserCode AuthStage = "USER_CODE" // Получен код подтверждения, пользователь должен ввести его на странице VerificationURL.
+	AuthStagePending  AuthStage = "PENDING"   // Пользователь еще не подтвердил доступ.
+	AuthStageSlowDown AuthStage = "SLOW_DOWN" // Сервер попросил опрашивать реже, интервал увеличен.
+)
+
+// AuthProgress состояние авторизации по коду подтверждения.
+type AuthProgress struct {
+	Stage           AuthStage
+	UserCode        string
+	VerificationURL string
+	ExpiresAt       time.Time     // Момент истечения кода подтверждения.
+	Interval        time.Duration // Текущий интервал опроса.
 }
 
-func (c *Client) Authorise(message chan<- string) error {
-	authData, err := c.GetAccessCode()
+type AuthProgressFunc func(AuthProgress)
+
+// Authorise выполняет авторизацию по коду подтверждения (RFC 8628) и возвращает токен.
+// Опрос прекращается при отмене ctx, истечении кода, отказе пользователя или получении токена.
+func (c *Client) Authorise(ctx context.Context, progress AuthProgressFunc) (Token, error) {
+	if progress == nil {
+		progress = func(AuthProgress) {}
+	}
+
+	authData, err := c.GetAccessCodeContext(ctx)
 	if err != nil {
-		return err
+		return Token{}, fmt.Errorf("GetAccessCode: %w", err)
+	}
+
+	interval := time.Duration(authData.Interval) * pollUnit
+	if interval <= 0 {
+		interval = defaultPollInterval * pollUnit
+	}
+
+	state := AuthProgress{
+		Stage:           AuthStageUserCode,
+		UserCode:        authData.UserCode,
+		VerificationURL: authData.VerificationURL,
+		Interval:        interval,
+	}
+
+	if authData.ExpiresIn > 0 {
+		state.ExpiresAt = time.Now().Add(time.Duration(authData.ExpiresIn) * pollUnit)
+
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithDeadline(ctx, state.ExpiresAt)
+		defer cancel()
 	}
 
-	message <- fmt.Sprintf("user code: %s | %s \n", authData.UserCode, authData.VerificationURL)
-	token := Token{}
-	done := make(chan bool, 1)
-	duration := time.Duration(authData.Interval)*time.Second + 20*time.Second
-	ticker := time.NewTicker(duration)
+	progress(state)
 
-	defer ticker.Stop()
+	timer := time.NewTimer(state.Interval)
+	defer timer.Stop()
 
-outerLoop:
 	for {
 		select {
-		case <-done:
-			message <- "nil"
-			close(message)
-			return nil
-		case <-ticker.C:
-			token, err = c.GetTokenByCode(authData)
-			if errors.Is(err, ErrAuthorisationPending) {
-				fmt.Println(err)
-
-				continue
-			} else if err != nil {
-				return err
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) && !state.ExpiresAt.IsZero() && !time.Now().Before(state.ExpiresAt) {
+				return Token{}, ErrExpiredToken
 			}
-			if token.AccessToken != "" {
-				done <- true
 
-				break outerLoop
-			}
+			return Token{}, ctx.Err() //nolint:wrapcheck
+		case <-timer.C:
 		}
-	}
 
-	//file, err := os.Create(fmt.Sprintf("%s.txt", c.Login))
-	//if err != nil {
-	//	message <- err.Error()
-	//	close(message)
-	//	return fmt.Errorf("failed to create file: %w", err)
-	//}
-	//
-	//defer file.Close()
-
-	//_, err = file.WriteString(token.AccessToken)
-	//if err != nil {
-	//	message <- err.Error()
-	//	close(message)
-	//	return fmt.Errorf("failed to write to file: %w", err)
-	//}
-	message <- "token: " + token.AccessToken
-	close(message)
-
-	return nil
+		token, err := c.GetTokenByCodeContext(ctx, authData)
+
+		switch {
+		case err == nil:
+			return token, nil
+		case errors.Is(err, ErrAuthorisationPending):
+			state.Stage = AuthStagePending
+		case errors.Is(err, ErrSlowDown):
+			state.Stage = AuthStageSlowDown
+			state.Interval += slowDownIncrement * pollUnit
+		case errors.Is(ctx.Err(), context.DeadlineExceeded) && !state.ExpiresAt.IsZero():
+			return Token{}, ErrExpiredToken
+		default:
+			return Token{}, fmt.Errorf("GetTokenByCode: %w", err)
+		}
+
+		progress(state)
+		timer.Reset(state.Interval)
+	}
 }
 
-func (c *Client) GetAccessCode() (auth AuthByCode, err error) {
-	host := "oauth.yandex.ru"
+// GetAccessCode запрашивает код подтверждения для авторизации по RFC 8628.
+func (c *Client) GetAccessCode() (AuthByCode, error) {
+	return c.GetAccessCodeContext(context.Background())
+}
 
+// GetAccessCodeContext запрашивает код подтверждения с контекстом ctx.
+func (c *Client) GetAccessCodeContext(ctx context.Context) (auth AuthByCode, err error) {
 	reqAccessURL := url.URL{
 		Scheme: "https",
-		Host:   host,
+		Host:   oauthHost,
 		Path:   "/device/code",
 	}
 	param := url.Values{}
 	param.Add("client_id", c.App.ID)
 
-	resp, err := c.Tr.PostForm(reqAccessURL.String(), param)
+	resp, reqDump, err := c.postForm(ctx, reqAccessURL.String(), param)
 	if err != nil {
 		return auth, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
+		respDump, _ := httputil.DumpResponse(resp, true)
+		c.logDumps(reqDump, respDump)
+
 		return auth, fmt.Errorf("StatusCode: %v", resp.StatusCode)
 	}
 
@@ -133,13 +205,17 @@ func (c *Client) GetAccessCode() (auth AuthByCode, err error) {
 	return auth, err
 }
 
-func (c *Client) GetTokenByCode(code AuthByCode) (token Token, err error) {
-	host := "oauth.yandex.ru"
+// GetTokenByCode обменивает код подтверждения на токен.
+func (c *Client) GetTokenByCode(code AuthByCode) (Token, error) {
+	return c.GetTokenByCodeContext(context.Background(), code)
+}
 
+// GetTokenByCodeContext обменивает код подтверждения на токен с контекстом ctx.
+func (c *Client) GetTokenByCodeContext(ctx context.Context, code AuthByCode) (token Token, err error) {
 	reqAccessURL := url.URL{
 		Scheme: "https",
-		Host:   host,
-		Path:   "token",
+		Host:   oauthHost,
+		Path:   "/token",
 	}
 	param := url.Values{
 		"client_id":     {c.App.ID},
@@ -148,36 +224,77 @@ func (c *Client) GetTokenByCode(code AuthByCode) (token Token, err error) {
 		"client_secret": {c.App.Secret},
 	}
 
-	resp, err := c.Tr.PostForm(reqAccessURL.String(), param)
+	return c.requestToken(ctx, reqAccessURL.String(), param)
+}
+
+// requestToken запрашивает токен у OAuth-сервера и разбирает ответ с ошибкой.
+func (c *Client) requestToken(ctx context.Context, rawURL string, param url.Values) (token Token, err error) {
+	resp, reqDump, err := c.postForm(ctx, rawURL, param)
 	if err != nil {
 		return token, err
 	}
 	defer resp.Body.Close()
 
-	//if resp.StatusCode != http.StatusOK {
-	//	return token, fmt.Errorf("StatusCode: %v", resp.StatusCode)
-	//}
-
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return token, err
 	}
 
-	err = json.Unmarshal(body, &token)
-	if err != nil {
-		return token, err
-	}
-
 	respErr := responseError{}
 
 	err = json.Unmarshal(body, &respErr)
 	if err != nil {
+		c.logDumps(reqDump, dumpResponseHead(resp, body))
+
 		return token, err
 	}
 
 	if respErr.Err != "" {
+		if !errors.Is(respErr, ErrAuthorisationPending) && !errors.Is(respErr, ErrSlowDown) {
+			c.logDumps(reqDump, dumpResponseHead(resp, body))
+		}
+
 		return token, respErr
 	}
 
-	return token, err
+	err = json.Unmarshal(body, &token)
+	if err != nil {
+		return token, err
+	}
+
+	token.setExpiry(time.Now())
+
+	return token, nil
+}
+
+// postForm отправляет форму и возвращает дамп запроса для логирования ошибок.
+func (c *Client) postForm(ctx context.Context, rawURL string, data url.Values) (*http.Response, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req = req.WithContext(WithCallInfo(ctx, CallInfo{
+		Service: "oauth",
+		Method:  strings.TrimPrefix(req.URL.Path, "/"),
+		Login:   c.Login,
+		Attempt: 1,
+		Started: time.Now(),
+	}))
+
+	reqDump, _ := httputil.DumpRequestOut(req, true)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, reqDump, err
+	}
+
+	return resp, reqDump, nil
+}
+
+func dumpResponseHead(resp *http.Response, body []byte) []byte {
+	head, _ := httputil.DumpResponse(resp, false)
+
+	return append(head, body...)
 }
@@ -0,0 +1,180 @@
+package yandex_direct_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// oauthServer отвечает на /device/code кодом подтверждения, а на /token — ответами tokens по порядку,
+// повторяя последний.
+type oauthServer struct {
+	code   AuthByCode
+	mu     sync.Mutex
+	tokens []string
+	polls  int
+}
+
+func (s *oauthServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/device/code":
+		_ = json.NewEncoder(w).Encode(s.code)
+	case "/token":
+		s.mu.Lock()
+		body := s.tokens[0]
+		if len(s.tokens) > 1 {
+			s.tokens = s.tokens[1:]
+		}
+		s.polls++
+		s.mu.Unlock()
+
+		var e responseError
+		if json.Unmarshal([]byte(body), &e) == nil && e.Err != "" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+
+		_, _ = w.Write([]byte(body))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// newOAuthClient направляет запросы клиента к OAuth на тестовый сервер.
+func newOAuthClient(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	target, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tr := RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		req.URL.Scheme = target.Scheme
+		req.URL.Host = target.Host
+
+		return http.DefaultTransport.RoundTrip(req)
+	})
+
+	return NewClient(&http.Client{Transport: tr}, "client", nil, &App{ID: "app", Secret: "secret"}, false, nil)
+}
+
+func oauthError(err responseError) string {
+	b, _ := json.Marshal(err)
+
+	return string(b)
+}
+
+func TestAuthorise(t *testing.T) {
+	defer func(unit time.Duration) { pollUnit = unit }(pollUnit)
+	pollUnit = 10 * time.Millisecond
+
+	const token = `{"token_type":"bearer","access_token":"access","refresh_token":"refresh","expires_in":3600}`
+
+	tests := []struct {
+		name      string
+		expiresIn int
+		tokens    []string
+		cancel    bool // Отменить контекст после первого ответа authorization_pending.
+		wantErr   error
+		stages    []AuthStage
+		intervals []time.Duration
+	}{
+		{
+			name:      "authorization pending",
+			tokens:    []string{oauthError(ErrAuthorisationPending), token},
+			stages:    []AuthStage{AuthStageUserCode, AuthStagePending},
+			intervals: []time.Duration{pollUnit, pollUnit},
+		},
+		{
+			name:      "slow down",
+			tokens:    []string{oauthError(ErrSlowDown), token},
+			stages:    []AuthStage{AuthStageUserCode, AuthStageSlowDown},
+			intervals: []time.Duration{pollUnit, 6 * pollUnit},
+		},
+		{
+			name:      "expired token",
+			tokens:    []string{oauthError(ErrExpiredToken)},
+			wantErr:   ErrExpiredToken,
+			stages:    []AuthStage{AuthStageUserCode},
+			intervals: []time.Duration{pollUnit},
+		},
+		{
+			name:      "access denied",
+			tokens:    []string{oauthError(ErrAccessDenied)},
+			wantErr:   ErrAccessDenied,
+			stages:    []AuthStage{AuthStageUserCode},
+			intervals: []time.Duration{pollUnit},
+		},
+		{
+			name:      "context cancelled",
+			tokens:    []string{oauthError(ErrAuthorisationPending)},
+			cancel:    true,
+			wantErr:   context.Canceled,
+			stages:    []AuthStage{AuthStageUserCode, AuthStagePending},
+			intervals: []time.Duration{pollUnit, pollUnit},
+		},
+		{
+			name:      "deadline from expires_in",
+			expiresIn: 3,
+			tokens:    []string{oauthError(ErrAuthorisationPending)},
+			wantErr:   ErrExpiredToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &oauthServer{
+				code:   AuthByCode{DeviceCode: "device", UserCode: "user", Interval: 1, ExpiresIn: tt.expiresIn},
+				tokens: tt.tokens,
+			}
+			c := newOAuthClient(t, srv)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var (
+				stages    []AuthStage
+				intervals []time.Duration
+			)
+
+			got, err := c.Authorise(ctx, func(p AuthProgress) {
+				stages = append(stages, p.Stage)
+				intervals = append(intervals, p.Interval)
+
+				if tt.cancel && p.Stage == AuthStagePending {
+					cancel()
+				}
+			})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v; want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && got.AccessToken != "access" {
+				t.Errorf("AccessToken = %q; want access", got.AccessToken)
+			}
+
+			if tt.stages != nil && !reflect.DeepEqual(stages, tt.stages) {
+				t.Errorf("stages = %v; want %v", stages, tt.stages)
+			}
+
+			if tt.intervals != nil && !reflect.DeepEqual(intervals, tt.intervals) {
+				t.Errorf("intervals = %v; want %v", intervals, tt.intervals)
+			}
+
+			if tt.expiresIn > 0 && srv.polls > tt.expiresIn {
+				t.Errorf("polled %d times after a code valid for %d intervals", srv.polls, tt.expiresIn)
+			}
+		})
+	}
+}
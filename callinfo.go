@@ -0,0 +1,81 @@
+package yandex_direct_sdk
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mg-realcom/yandex-direct-sdk/statistics"
+)
+
+// CallInfo описывает вызов API, к которому относится исходящий запрос. Доступен в Middleware через контекст запроса.
+type CallInfo struct {
+	Service    string                // Сервис API: reports, clients, oauth и т. д.
+	Method     string                // Метод сервиса.
+	Login      string                // Логин клиента, от имени которого выполняется запрос.
+	ReportName string                // Имя отчета, только для сервиса reports.
+	ReportType statistics.ReportType // Тип отчета, только для сервиса reports.
+	Attempt    int                   // Номер попытки, начиная с 1.
+	QueueWait  time.Duration         // Суммарное время ожидания отчета в очереди до этой попытки.
+	Started    time.Time             // Время первой попытки.
+}
+
+// Retries количество повторов перед текущей попыткой.
+func (i CallInfo) Retries() int {
+	if i.Attempt < 1 {
+		return 0
+	}
+
+	return i.Attempt - 1
+}
+
+type callInfoKey struct{}
+
+// WithCallInfo возвращает контекст с описанием вызова.
+func WithCallInfo(ctx context.Context, info CallInfo) context.Context {
+	return context.WithValue(ctx, callInfoKey{}, info)
+}
+
+// CallInfoFromContext возвращает описание вызова из контекста запроса.
+func CallInfoFromContext(ctx context.Context) (CallInfo, bool) {
+	info, ok := ctx.Value(callInfoKey{}).(CallInfo)
+
+	return info, ok
+}
+
+// Units баллы API из заголовка Units ответа.
+type Units struct {
+	Spent int64 // Израсходовано при выполнении запроса.
+	Rest  int64 // Доступный остаток.
+	Limit int64 // Суточный лимит.
+}
+
+const (
+	HeaderUnits          = "Units"
+	HeaderRequestID      = "RequestId"
+	HeaderRetryIn        = "retryIn"
+	HeaderReportsInQueue = "reportsInQueue"
+)
+
+// ParseUnits разбирает заголовок Units вида «израсходовано/остаток/лимит».
+func ParseUnits(h http.Header) (Units, bool) {
+	parts := strings.Split(h.Get(HeaderUnits), "/")
+	if len(parts) != 3 {
+		return Units{}, false
+	}
+
+	values := make([]int64, 0, len(parts))
+
+	for _, part := range parts {
+		v, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			return Units{}, false
+		}
+
+		values = append(values, v)
+	}
+
+	return Units{Spent: values[0], Rest: values[1], Limit: values[2]}, true
+}
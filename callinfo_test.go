@@ -0,0 +1,45 @@
+package yandex_direct_sdk
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestParseUnits(t *testing.T) {
+	tests := []struct {
+		header string
+		want   Units
+		ok     bool
+	}{
+		{header: "10/19990/20000", want: Units{Spent: 10, Rest: 19990, Limit: 20000}, ok: true},
+		{header: " 1 / 2 / 3 ", want: Units{Spent: 1, Rest: 2, Limit: 3}, ok: true},
+		{header: "", ok: false},
+		{header: "10/20", ok: false},
+		{header: "a/b/c", ok: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseUnits(http.Header{HeaderUnits: {tt.header}})
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseUnits(%q) = %+v, %v; want %+v, %v", tt.header, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestCallInfoContext(t *testing.T) {
+	if _, ok := CallInfoFromContext(context.Background()); ok {
+		t.Fatal("empty context has call info")
+	}
+
+	info := CallInfo{Service: "reports", Method: "get", Login: "client", Attempt: 3}
+
+	got, ok := CallInfoFromContext(WithCallInfo(context.Background(), info))
+	if !ok || got.Login != "client" || got.Retries() != 2 {
+		t.Errorf("got %+v, %v", got, ok)
+	}
+
+	if (CallInfo{}).Retries() != 0 {
+		t.Error("zero attempt has retries")
+	}
+}
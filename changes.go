@@ -0,0 +1,185 @@
+package yandex_direct_sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mg-realcom/yandex-direct-sdk/changes"
+	"github.com/mg-realcom/yandex-direct-sdk/internal/idutil"
+)
+
+// ErrUnprocessed сервер повторно не обработал объекты проверки изменений.
+var ErrUnprocessed = errors.New("changes check left objects unprocessed")
+
+// CheckCampaigns возвращает кампании, изменившиеся с момента timestamp.
+func (c *Client) CheckCampaigns(ctx context.Context, timestamp string) (changes.CheckCampaignsResult, error) {
+	var result changes.CheckCampaignsResult
+	if err := c.Call(ctx, "changes", "checkCampaigns", changes.CheckCampaignsParams{Timestamp: timestamp}, &result); err != nil {
+		return result, fmt.Errorf("changes.checkCampaigns: %w", err)
+	}
+
+	return result, nil
+}
+
+// CheckChanges возвращает изменения в кампаниях, группах и объявлениях с момента params.Timestamp.
+func (c *Client) CheckChanges(ctx context.Context, params changes.CheckParams) (changes.CheckResult, error) {
+	var result changes.CheckResult
+	if err := c.Call(ctx, "changes", "check", params, &result); err != nil {
+		return result, fmt.Errorf("changes.check: %w", err)
+	}
+
+	return result, nil
+}
+
+// CheckDictionaries проверяет, изменились ли справочники с момента timestamp. Пустой timestamp
+// возвращает только текущее время сервера.
+func (c *Client) CheckDictionaries(ctx context.Context, timestamp string) (changes.CheckDictionariesResult, error) {
+	var result changes.CheckDictionariesResult
+	if err := c.Call(ctx, "changes", "checkDictionaries", changes.CheckDictionariesParams{Timestamp: timestamp}, &result); err != nil {
+		return result, fmt.Errorf("changes.checkDictionaries: %w", err)
+	}
+
+	return result, nil
+}
+
+// ModifiedSince возвращает объекты Client.Login, изменившиеся с прошлой синхронизации. State не изменяется:
+// время сервера для следующей синхронизации возвращается в Modified.Timestamp, и вызывающий сохраняет его
+// через State.Commit после обработки объектов. При первой синхронизации возвращает Modified.Full.
+func (c *Client) ModifiedSince(ctx context.Context, state *changes.State) (changes.Modified, error) {
+	since := state.Timestamp(c.Login)
+	modified := changes.Modified{Since: since}
+
+	if since == "" {
+		check, err := c.CheckDictionaries(ctx, "")
+		if err != nil {
+			return modified, err
+		}
+
+		modified.Full = true
+		modified.Timestamp = check.Timestamp
+
+		return modified, nil
+	}
+
+	campaigns, err := c.CheckCampaigns(ctx, since)
+	if err != nil {
+		return modified, err
+	}
+
+	modified.Timestamp = campaigns.Timestamp
+
+	var check []int64
+
+	for _, item := range campaigns.Campaigns {
+		if item.Has(changes.ChangesInSelf) {
+			modified.CampaignIDs = append(modified.CampaignIDs, item.CampaignID)
+		}
+
+		if item.Has(changes.ChangesInStat) {
+			modified.StatCampaignIDs = append(modified.StatCampaignIDs, item.CampaignID)
+		}
+
+		if item.Has(changes.ChangesInChildren) || item.Has(changes.ChangesInStat) {
+			check = append(check, item.CampaignID)
+		}
+	}
+
+	if err := c.collectModified(ctx, since, check, &modified); err != nil {
+		return modified, err
+	}
+
+	return modified, nil
+}
+
+// collectModified запрашивает изменившиеся группы, объявления и статистику кампаний пачками по
+// MaxCheckCampaignIDs. Объекты из Unprocessed запрашиваются повторно отдельными пачками; если сервер
+// не обработал ни одного объекта пачки, возвращается ErrUnprocessed и время синхронизации не сдвигается.
+func (c *Client) collectModified(ctx context.Context, since string, campaignIDs []int64, modified *changes.Modified) error {
+	fields := []changes.CheckFieldName{changes.FieldAdGroupIDs, changes.FieldAdIDs}
+
+	campaignFields := fields
+	if len(modified.StatCampaignIDs) > 0 {
+		campaignFields = append(campaignFields[:len(fields):len(fields)], changes.FieldCampaignsStat)
+	}
+
+	queue := checkBatches(changes.IDs{CampaignIDs: campaignIDs})
+
+	for len(queue) > 0 {
+		batch := queue[0]
+		queue = queue[1:]
+
+		params := changes.CheckParams{
+			CampaignIDs: batch.CampaignIDs,
+			AdGroupIDs:  batch.AdGroupIDs,
+			AdIDs:       batch.AdIDs,
+			FieldNames:  fields,
+			Timestamp:   since,
+		}
+		if len(batch.CampaignIDs) > 0 {
+			params.FieldNames = campaignFields
+		}
+
+		result, err := c.CheckChanges(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		if result.Modified != nil {
+			modified.AdGroupIDs = append(modified.AdGroupIDs, result.Modified.AdGroupIDs...)
+			modified.AdIDs = append(modified.AdIDs, result.Modified.AdIDs...)
+		}
+
+		modified.CampaignsStat = append(modified.CampaignsStat, result.CampaignsStat...)
+
+		if result.Unprocessed == nil || result.Unprocessed.Len() == 0 {
+			continue
+		}
+
+		if result.Unprocessed.Len() >= batch.Len() {
+			return fmt.Errorf("%w: %d objects", ErrUnprocessed, result.Unprocessed.Len())
+		}
+
+		queue = append(queue, checkBatches(*result.Unprocessed)...)
+	}
+
+	idutil.Sort(modified.AdGroupIDs)
+	idutil.Sort(modified.AdIDs)
+
+	return nil
+}
+
+// checkBatches разбивает идентификаторы на пачки запросов check: в одном запросе только один тип объектов.
+func checkBatches(ids changes.IDs) []changes.IDs {
+	var out []changes.IDs
+
+	for _, part := range chunkIDs(ids.CampaignIDs, changes.MaxCheckCampaignIDs) {
+		out = append(out, changes.IDs{CampaignIDs: part})
+	}
+
+	for _, part := range chunkIDs(ids.AdGroupIDs, changes.MaxCheckAdGroupIDs) {
+		out = append(out, changes.IDs{AdGroupIDs: part})
+	}
+
+	for _, part := range chunkIDs(ids.AdIDs, changes.MaxCheckAdIDs) {
+		out = append(out, changes.IDs{AdIDs: part})
+	}
+
+	return out
+}
+
+func chunkIDs(ids []int64, size int) [][]int64 {
+	var out [][]int64
+
+	for len(ids) > 0 {
+		n := len(ids)
+		if n > size {
+			n = size
+		}
+
+		out = append(out, ids[:n])
+		ids = ids[n:]
+	}
+
+	return out
+}
@@ -0,0 +1,125 @@
+package changes
+
+import (
+	"github.com/mg-realcom/yandex-direct-sdk/common"
+	"github.com/mg-realcom/yandex-direct-sdk/dictionaries"
+)
+
+const (
+	MaxCheckCampaignIDs = 3000   // Максимальное количество кампаний в одном запросе check.
+	MaxCheckAdGroupIDs  = 10_000 // Максимальное количество групп в одном запросе check.
+	MaxCheckAdIDs       = 50_000 // Максимальное количество объявлений в одном запросе check.
+)
+
+type ChangesIn string
+
+const (
+	ChangesInSelf     ChangesIn = "SELF"     // Изменились параметры самой кампании.
+	ChangesInChildren ChangesIn = "CHILDREN" // Изменились группы, объявления или условия показа кампании.
+	ChangesInStat     ChangesIn = "STAT"     // Изменилась статистика кампании.
+)
+
+type CheckCampaignsParams struct {
+	Timestamp string `json:"Timestamp"` // Время, начиная с которого проверяются изменения.
+}
+
+type CheckCampaignsResult struct {
+	Campaigns []CampaignChangesItem `json:"Campaigns,omitempty"`
+	Timestamp string                `json:"Timestamp"` // Текущее время сервера для следующей проверки.
+}
+
+type CampaignChangesItem struct {
+	CampaignID int64       `json:"CampaignId"`
+	ChangesIn  []ChangesIn `json:"ChangesIn"`
+}
+
+// Has сообщает, что кампания изменилась в части in.
+func (i CampaignChangesItem) Has(in ChangesIn) bool {
+	for _, v := range i.ChangesIn {
+		if v == in {
+			return true
+		}
+	}
+
+	return false
+}
+
+type CheckFieldName string
+
+const (
+	FieldCampaignIDs   CheckFieldName = "CampaignIds"
+	FieldAdGroupIDs    CheckFieldName = "AdGroupIds"
+	FieldAdIDs         CheckFieldName = "AdIds"
+	FieldCampaignsStat CheckFieldName = "CampaignsStat"
+)
+
+type CheckParams struct {
+	CampaignIDs []int64          `json:"CampaignIds,omitempty"` // Кампании, изменения в которых нужно проверить. Не более 3000.
+	AdGroupIDs  []int64          `json:"AdGroupIds,omitempty"`  // Группы, изменения в которых нужно проверить. Не более 10 000.
+	AdIDs       []int64          `json:"AdIds,omitempty"`       // Объявления, изменения в которых нужно проверить. Не более 50 000.
+	FieldNames  []CheckFieldName `json:"FieldNames"`            // Какие изменения нужно вернуть.
+	Timestamp   string           `json:"Timestamp"`             // Время, начиная с которого проверяются изменения.
+}
+
+type CheckResult struct {
+	Modified      *IDs               `json:"Modified,omitempty"`      // Изменившиеся объекты.
+	NotFound      *IDs               `json:"NotFound,omitempty"`      // Объекты, не найденные или удаленные.
+	Unprocessed   *IDs               `json:"Unprocessed,omitempty"`   // Объекты, не обработанные из-за ограничения на объем ответа.
+	CampaignsStat []CampaignStatItem `json:"CampaignsStat,omitempty"` // Кампании с изменившейся статистикой.
+	Timestamp     string             `json:"Timestamp"`
+}
+
+type IDs struct {
+	CampaignIDs []int64 `json:"CampaignIds,omitempty"`
+	AdGroupIDs  []int64 `json:"AdGroupIds,omitempty"`
+	AdIDs       []int64 `json:"AdIds,omitempty"`
+}
+
+// Len возвращает общее количество идентификаторов.
+func (i IDs) Len() int {
+	return len(i.CampaignIDs) + len(i.AdGroupIDs) + len(i.AdIDs)
+}
+
+type CampaignStatItem struct {
+	CampaignID int64  `json:"CampaignId"`
+	BorderDate string `json:"BorderDate"` // Дата, начиная с которой изменилась статистика, YYYY-MM-DD.
+}
+
+type CheckDictionariesParams struct {
+	Timestamp string `json:"Timestamp,omitempty"`
+}
+
+// CheckDictionariesResult признаки изменения справочников с момента Timestamp запроса.
+type CheckDictionariesResult struct {
+	RegionsChanged   common.YesNo `json:"RegionsChanged,omitempty"`   // Изменились регионы или станции метро.
+	TimeZonesChanged common.YesNo `json:"TimeZonesChanged,omitempty"` // Изменились часовые пояса.
+	InterestsChanged common.YesNo `json:"InterestsChanged,omitempty"` // Изменились интересы.
+	Timestamp        string       `json:"Timestamp"`                  // Текущее время сервера для следующей проверки.
+}
+
+// Changed сообщает, изменился ли справочник по данным проверки. Для справочников, которые
+// checkDictionaries не отслеживает, возвращает tracked=false.
+func (r CheckDictionariesResult) Changed(name dictionaries.Name) (changed, tracked bool) {
+	switch name { //nolint:exhaustive
+	case dictionaries.GeoRegions, dictionaries.MetroStations:
+		return r.RegionsChanged == common.YES, true
+	case dictionaries.TimeZones:
+		return r.TimeZonesChanged == common.YES, true
+	case dictionaries.Interests:
+		return r.InterestsChanged == common.YES, true
+	default:
+		return false, false
+	}
+}
+
+// Modified объекты аккаунта, изменившиеся с момента прошлой синхронизации.
+type Modified struct {
+	Since           string  // Время прошлой синхронизации. Пусто при первой синхронизации.
+	Timestamp       string  // Время сервера для следующей синхронизации, сохраняется через State.Commit.
+	Full            bool    // Прошлой синхронизации не было, нужно загрузить все объекты.
+	CampaignIDs     []int64 // Кампании с изменившимися собственными параметрами.
+	AdGroupIDs      []int64 // Изменившиеся группы объявлений.
+	AdIDs           []int64 // Изменившиеся объявления.
+	StatCampaignIDs []int64 // Кампании с изменившейся статистикой.
+	CampaignsStat   []CampaignStatItem
+}
@@ -0,0 +1,68 @@
+package changes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/mg-realcom/yandex-direct-sdk/internal/fsutil"
+)
+
+const filePerm = 0o644
+
+// State время последней синхронизации по логинам, хранящееся в JSON-файле.
+type State struct {
+	path       string
+	mu         sync.Mutex
+	timestamps map[string]string
+}
+
+// LoadState читает состояние из файла. Отсутствующий файл означает пустое состояние.
+func LoadState(path string) (*State, error) {
+	s := &State{path: path, timestamps: map[string]string{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("read state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &s.timestamps); err != nil {
+		return nil, fmt.Errorf("unmarshal state: %w", err)
+	}
+
+	return s, nil
+}
+
+// Timestamp возвращает время последней синхронизации логина.
+func (s *State) Timestamp(login string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.timestamps[login]
+}
+
+// Commit задает время последней синхронизации логина и сохраняет состояние. Вызывается после того,
+// как изменившиеся объекты обработаны, иначе при сбое обработки они будут потеряны.
+func (s *State) Commit(login, timestamp string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.timestamps[login] = timestamp
+
+	data, err := json.MarshalIndent(s.timestamps, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal state: %w", err)
+	}
+
+	if err := fsutil.WriteFile(s.path, ".changes_*", data, filePerm); err != nil {
+		return fmt.Errorf("save state: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package yandex_direct_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/mg-realcom/yandex-direct-sdk/changes"
+)
+
+// bodyRecorder запоминает тела запросов перед передачей их в next.
+type bodyRecorder struct {
+	next   http.RoundTripper
+	bodies []string
+}
+
+func (b *bodyRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	b.bodies = append(b.bodies, string(body))
+
+	return b.next.RoundTrip(req)
+}
+
+func TestModifiedSinceUnprocessed(t *testing.T) {
+	checkCampaigns := stubResponse{status: http.StatusOK, body: `{"result":{"Timestamp":"T1","Campaigns":[
+		{"CampaignId":1,"ChangesIn":["CHILDREN"]},{"CampaignId":2,"ChangesIn":["CHILDREN"]}]}}`}
+
+	tests := []struct {
+		name      string
+		responses []stubResponse
+		want      []int64
+		err       error
+		timestamp string
+		retried   []int64
+	}{
+		{
+			name: "unprocessed retried",
+			responses: []stubResponse{
+				checkCampaigns,
+				{status: http.StatusOK, body: `{"result":{"Timestamp":"T1","Modified":{"AdGroupIds":[5]},"Unprocessed":{"CampaignIds":[2]}}}`},
+				{status: http.StatusOK, body: `{"result":{"Timestamp":"T1","Modified":{"AdGroupIds":[3]}}}`},
+			},
+			want:      []int64{3, 5},
+			timestamp: "T1",
+			retried:   []int64{2},
+		},
+		{
+			name: "nothing processed",
+			responses: []stubResponse{
+				checkCampaigns,
+				{status: http.StatusOK, body: `{"result":{"Timestamp":"T1","Unprocessed":{"CampaignIds":[1,2]}}}`},
+			},
+			err: ErrUnprocessed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, err := changes.LoadState(filepath.Join(t.TempDir(), "changes.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := state.Commit("client", "T0"); err != nil {
+				t.Fatal(err)
+			}
+
+			rec := &bodyRecorder{next: &stubTransport{responses: tt.responses}}
+			c, _ := newLoggedClient(rec)
+
+			modified, err := c.ModifiedSince(context.Background(), state)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("ModifiedSince() error = %v; want %v", err, tt.err)
+			}
+
+			if err == nil && !reflect.DeepEqual(modified.AdGroupIDs, tt.want) {
+				t.Errorf("AdGroupIDs = %v; want %v", modified.AdGroupIDs, tt.want)
+			}
+
+			if err == nil && modified.Timestamp != tt.timestamp {
+				t.Errorf("Timestamp = %q; want %q", modified.Timestamp, tt.timestamp)
+			}
+
+			if got := state.Timestamp("client"); got != "T0" {
+				t.Errorf("saved timestamp = %q; want T0 until Commit", got)
+			}
+
+			if tt.retried == nil {
+				return
+			}
+
+			var last struct {
+				Params changes.CheckParams `json:"params"`
+			}
+			if err := json.Unmarshal([]byte(rec.bodies[len(rec.bodies)-1]), &last); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(last.Params.CampaignIDs, tt.retried) {
+				t.Errorf("retried CampaignIds = %v; want %v", last.Params.CampaignIDs, tt.retried)
+			}
+		})
+	}
+}
+
+func TestModifiedSinceCommit(t *testing.T) {
+	errConsumer := errors.New("consumer failed")
+
+	tests := []struct {
+		name    string
+		since   string
+		consume error
+		want    string
+		full    bool
+	}{
+		{name: "first sync", want: "T1", full: true},
+		{name: "first sync failed", consume: errConsumer, want: "", full: true},
+		{name: "consumed", since: "T0", want: "T1"},
+		{name: "consumer failed", since: "T0", consume: errConsumer, want: "T0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "changes.json")
+
+			state, err := changes.LoadState(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.since != "" {
+				if err := state.Commit("client", tt.since); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			c, _ := newLoggedClient(&stubTransport{responses: []stubResponse{
+				{status: http.StatusOK, body: `{"result":{"Timestamp":"T1","Campaigns":[]}}`},
+			}})
+
+			modified, err := c.ModifiedSince(context.Background(), state)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if modified.Full != tt.full || modified.Timestamp != "T1" {
+				t.Errorf("modified = %+v; want Full %v and Timestamp T1", modified, tt.full)
+			}
+
+			if tt.consume == nil {
+				if err := state.Commit("client", modified.Timestamp); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			reloaded, err := changes.LoadState(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := reloaded.Timestamp("client"); got != tt.want {
+				t.Errorf("stored timestamp = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
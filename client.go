@@ -3,16 +3,21 @@ package yandex_direct_sdk
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/mg-realcom/yandex-direct-sdk/common"
+	"github.com/mg-realcom/yandex-direct-sdk/redact"
+	"github.com/mg-realcom/yandex-direct-sdk/reportcache"
 	"github.com/mg-realcom/yandex-direct-sdk/statistics"
 	"github.com/rs/zerolog"
 	"io"
 	"net/http"
 	"net/http/httputil"
 	"os"
+	"path/filepath"
 	"strconv"
 	"time"
 )
@@ -25,6 +30,16 @@ type Client struct {
 	host            environment
 	statisticsLimit statisticsLimits
 	logger          *zerolog.Logger
+	redactor        *redact.Redactor
+	middlewares     []Middleware
+	tokenSource     TokenSource
+	limiter         RateLimiter
+	units           *UnitsAccounting
+	profile         *accountCache
+	language        string
+	reportCache     *reportcache.Cache
+	nameConflict    NameConflictPolicy
+	compression     Compression
 }
 
 type App struct {
@@ -39,18 +54,17 @@ type statisticsLimits struct {
 
 type environment string
 
+const defaultLanguage = "ru"
+
 const (
 	LIVE    environment = "api.direct.yandex.com"
 	SANDBOX environment = "api-sandbox.direct.yandex.com"
 )
 
 func NewClient(tr *http.Client, login string, token *string, app *App, sandbox bool, logger *zerolog.Logger) *Client {
+	host := LIVE
 	if sandbox {
-		return &Client{
-			Login: login,
-			Token: token,
-			host:  SANDBOX,
-		}
+		host = SANDBOX
 	}
 
 	return &Client{
@@ -58,21 +72,72 @@ func NewClient(tr *http.Client, login string, token *string, app *App, sandbox b
 		Login: login,
 		Token: token,
 		App:   app,
-		host:  LIVE,
+		host:  host,
 		statisticsLimit: statisticsLimits{
 			retryInterval:  0,
 			reportsInQueue: 0,
 		},
-		logger: logger,
+		logger:   logger,
+		redactor: redact.Default(),
+		units:    newUnitsAccounting(),
+		profile:  &accountCache{},
+	}
+}
+
+// WithLogin возвращает клиента для другого логина. Новый клиент использует тот же токен, транспорт,
+// Middleware, ограничитель частоты запросов и учет баллов, но собственную очередь отчетов.
+func (c *Client) WithLogin(login string) *Client {
+	sub := *c
+	sub.Login = login
+	sub.statisticsLimit = statisticsLimits{}
+	sub.profile = &accountCache{}
+	sub.middlewares = append([]Middleware(nil), c.middlewares...)
+
+	return &sub
+}
+
+// SetRedactor задает правила скрытия данных в логируемых дампах. Nil отключает скрытие.
+func (c *Client) SetRedactor(r *redact.Redactor) {
+	c.redactor = r
+}
+
+func (c *Client) logDumps(reqDump, respDump []byte) {
+	if c.logger == nil {
+		return
+	}
+
+	c.logger.Info().Msg(fmt.Sprintf("REQUEST:\n%s", c.redactor.Dump(reqDump)))
+	c.logger.Info().Msg(fmt.Sprintf("RESPONSE:\n%s", c.redactor.Dump(respDump)))
+}
+
+// WithLanguage возвращает копию клиента, запрашивающую ответы на языке lang: ru, en, uk, tr, uz, kk.
+func (c *Client) WithLanguage(lang string) *Client {
+	sub := *c
+	sub.language = lang
+	sub.middlewares = append([]Middleware(nil), c.middlewares...)
+
+	return &sub
+}
+
+func (c *Client) lang() string {
+	if c.language == "" {
+		return defaultLanguage
 	}
+
+	return c.language
 }
 
-func (c *Client) buildHeader(req *http.Request) {
-	req.Header.Add("Authorization", "Bearer "+*c.Token)
-	req.Header.Add("Client-Login", c.Login)
-	req.Header.Add("Accept-Language", "ru")
-	req.Header.Add("skipReportHeader", "true")
-	req.Header.Add("skipReportSummary", "true")
+func (c *Client) buildHeader(req *http.Request) error {
+	token, err := c.accessToken(req.Context())
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Client-Login", c.Login)
+	req.Header.Set("Accept-Language", c.lang())
+
+	return nil
 }
 
 type Payload struct {
@@ -90,13 +155,9 @@ type Payload struct {
 	} `json:"params"`
 }
 
+// GetReport получает отчет за период dateRange. Имя отчета детерминировано (см. ReportName),
+// поэтому повторный запрос того же отчета не ставит в очередь новый.
 func (c *Client) GetReport(ctx context.Context, prefixTitleRequest, dir string, typeReport statistics.ReportType, fields []string, filter []statistics.Filter, dateRange statistics.DateRange) ([]string, error) {
-	t := time.Now().Format("2006-01-02")
-	var reportName string
-	var dtRangeType statistics.DateRangeType
-
-	reportName = fmt.Sprintf("%s_%s_%s_%s_%s", c.Login, prefixTitleRequest, dateRange.From, dateRange.To, t)
-	dtRangeType = statistics.DateRangeCustomDate
 	params := statistics.ReportDefinition{
 		Selection: &statistics.SelectionCriteria{
 			DateFrom: dateRange.From,
@@ -105,12 +166,12 @@ func (c *Client) GetReport(ctx context.Context, prefixTitleRequest, dir string,
 		},
 		FieldNames:    fields,
 		Page:          &common.Page{Limit: 50_000, Offset: 0},
-		ReportName:    reportName,
 		ReportType:    typeReport,
-		DateRangeType: dtRangeType,
+		DateRangeType: statistics.DateRangeCustomDate,
 		Format:        common.FormatTSV,
 		IncludeVAT:    common.NO,
 	}
+	params.ReportName = c.ReportName(fmt.Sprintf("%s_%s_%s", prefixTitleRequest, dateRange.From, dateRange.To), params)
 
 	fileNames, err := c.GetFiles(ctx, dir, params)
 	if err != nil {
@@ -119,63 +180,181 @@ func (c *Client) GetReport(ctx context.Context, prefixTitleRequest, dir string,
 	return fileNames, nil
 }
 
+// SetReportCache включает кэширование отчетов. Nil отключает кэш.
+func (c *Client) SetReportCache(cache *reportcache.Cache) {
+	c.reportCache = cache
+}
+
+// GetFiles получает отчет и сохраняет его части в dir. Если задан кэш отчетов и период отчета
+// закончился раньше вчерашнего дня, части копируются из кэша без запроса к API.
+// Определение с целями больше statistics.MaxGoals отклоняется с ErrTooManyGoals (см. GetGoalReport).
 func (c *Client) GetFiles(ctx context.Context, dir string, params statistics.ReportDefinition) ([]string, error) {
+	if c.reportCache == nil || !c.reportCache.Cacheable(params) {
+		files, _, err := c.getFiles(ctx, dir, params, false)
+
+		return files, err
+	}
+
+	key := statistics.Hash(params, c.Login, c.reportHeaders())
+
+	cached, ok, err := c.reportCache.Get(key)
+	if err != nil && c.logger != nil {
+		c.logger.Warn().Err(err).Msg("report cache")
+	}
+
+	if ok {
+		return reportcache.Restore(cached, dir, func(part int) string {
+			return fmt.Sprintf("%s_part_%d", params.ReportName, part)
+		})
+	}
+
+	files, _, err := c.getFiles(ctx, dir, params, false)
+	if err != nil {
+		return files, err
+	}
+
+	entry := reportcache.Entry{
+		Key:        key,
+		Login:      c.Login,
+		ReportType: params.ReportType,
+		DateFrom:   params.Selection.DateFrom,
+		DateTo:     params.Selection.DateTo,
+	}
+	if err := c.reportCache.Put(entry, files); err != nil && c.logger != nil {
+		c.logger.Warn().Err(err).Msg("report cache")
+	}
+
+	return files, nil
+}
+
+// GetFilesWithPeriod получает отчет как GetFiles, но без кэша, и возвращает период, за который Директ
+// сформировал отчет. Нужен для периода AUTO, границы которого определяет сервер. Каждая часть начинается
+// со строки с названием и периодом отчета; OpenReport и ReadReports ее пропускают.
+func (c *Client) GetFilesWithPeriod(ctx context.Context, dir string, params statistics.ReportDefinition) ([]string, statistics.DateRange, error) {
+	files, period, err := c.getFiles(ctx, dir, params, true)
+	if err == nil && period.From == "" {
+		err = ErrNoReportPeriod
+	}
+
+	return files, period, err
+}
+
+// getFiles загружает отчет постранично. withTitle запрашивает строку с названием и периодом отчета
+// в начале каждой части; период возвращается только в этом случае.
+func (c *Client) getFiles(ctx context.Context, dir string, params statistics.ReportDefinition, withTitle bool) ([]string, statistics.DateRange, error) {
 	var result []string
+	var period statistics.DateRange
+	if params.Goals != nil && len(*params.Goals) > statistics.MaxGoals {
+		// Директ отклоняет такой отчет; GetGoalReport разбивает цели на несколько отчетов.
+		return nil, period, fmt.Errorf("%w: %d, use GetGoalReport", ErrTooManyGoals, len(*params.Goals))
+	}
 	part := 1
 	reportName := params.ReportName
-	params.ReportName += fmt.Sprintf("_part_%d", part)
-	fieldsSize := 0
-	for _, field := range params.FieldNames {
-		fieldsSize += len([]byte(field))
+	if params.Page == nil {
+		params.Page = &common.Page{Limit: 50_000}
+	} else {
+		page := *params.Page
+		params.Page = &page
+	}
+	if params.Page.Limit <= 0 {
+		params.Page.Limit = 50_000
+	}
+	manifestFile := manifestPath(dir, reportName)
+	headers := c.reportHeaders()
+	headerLines := reportHeaderLines
+	if withTitle {
+		headers["skipReportHeader"] = "false"
+		headerLines++
 	}
-	fieldsSize += len(params.FieldNames)
+	m := c.resumeManifest(manifestFile, dir, params, headers)
+	if n := len(m.Parts); n > 0 {
+		result = m.files(dir)
+		part = m.Parts[n-1].Part + 1
+		params.Page.Offset = m.Parts[n-1].Offset + params.Page.Limit
+		reportName = m.ReportName
+	}
+	params.ReportName = reportName + fmt.Sprintf("_part_%d", part)
+	info := CallInfo{Service: "reports", Method: "get", Login: c.Login, ReportType: params.ReportType, Started: time.Now()}
+	baseName, renames := reportName, 0
 	for {
-		req, err := c.createGetReportRequest(ctx, params)
+		c.waitInfo(params.ReportName)
+		wait := time.Duration(c.statisticsLimit.retryInterval) * time.Second
+		time.Sleep(wait)
+		info.QueueWait += wait
+		info.Attempt++
+		info.ReportName = params.ReportName
+		req, err := c.createGetReportRequest(WithCallInfo(ctx, info), params, headers)
 		if err != nil {
-			return result, fmt.Errorf("createGetReportRequest: %w", err)
+			return result, period, fmt.Errorf("createGetReportRequest: %w", err)
 		}
 		reqDump, _ := httputil.DumpRequestOut(req, true)
-		c.waitInfo(params.ReportName)
-		time.Sleep(time.Duration(c.statisticsLimit.retryInterval) * time.Second)
-		resp, err := c.Tr.Do(req)
+		resp, err := c.doAuthorized(req)
 		if err != nil {
-			return result, fmt.Errorf("do request: %w", err)
+			return result, period, fmt.Errorf("do request: %w", err)
 		}
-		respDump, _ := httputil.DumpResponse(resp, true)
 
 		switch resp.StatusCode {
 		case http.StatusOK:
-			file, fileSize, err := createTSVFile(dir, params.ReportName, resp)
+			saved, err := createTSVFile(dir, params.ReportName, resp, c.compression)
 			if err != nil {
-				return result, fmt.Errorf("createTSVFile: %w", err)
+				return result, period, fmt.Errorf("createTSVFile: %w", err)
 			}
-			if fieldsSize < fileSize {
-				result = append(result, file)
-				params.Page.Offset += params.Page.Limit
-				part++
-				params.ReportName = reportName + fmt.Sprintf("_part_%d", part)
-				continue
+			// Страница, в которой строк меньше Page.Limit, последняя: так пагинация завершается и для отчетов,
+			// строки которых короче заголовка, например при разбивке по целям.
+			if withTitle && period.From == "" {
+				period, _ = readReportPeriod(saved.path)
+			}
+			rows := saved.lines - headerLines
+			if rows > 0 {
+				result = append(result, saved.path)
 			} else {
-				_ = os.Remove(file)
-				return result, nil
+				_ = os.Remove(saved.path)
+			}
+			if rows < params.Page.Limit {
+				_ = os.Remove(manifestFile)
+				return result, period, nil
 			}
+			m.Parts = append(m.Parts, ManifestPart{
+				Part:   part,
+				Offset: params.Page.Offset,
+				File:   filepath.Base(saved.path),
+				Size:   saved.size,
+				SHA256: saved.sum,
+			})
+			if err := m.save(manifestFile); err != nil {
+				return result, period, err
+			}
+			params.Page.Offset += params.Page.Limit
+			part++
+			params.ReportName = reportName + fmt.Sprintf("_part_%d", part)
+			info.Attempt, info.QueueWait, info.Started = 0, 0, time.Now()
 		case http.StatusCreated, http.StatusAccepted:
+			resp.Body.Close()
 			err := c.waitInit(resp)
 			if err != nil {
-				return result, fmt.Errorf("waitInit: %w", err)
+				return result, period, fmt.Errorf("waitInit: %w", err)
 			}
 		case http.StatusInternalServerError:
-			c.logger.Info().Msg(fmt.Sprintf("REQUEST:\n%s", reqDump))
-			c.logger.Info().Msg(fmt.Sprintf("RESPONSE:\n%s", respDump))
-			return result, errors.New("internal server error")
+			respDump, _ := httputil.DumpResponse(resp, true)
+			resp.Body.Close()
+			c.logDumps(reqDump, respDump)
+			return result, period, errors.New("internal server error")
 		case http.StatusBadRequest:
-			data, err := c.badRequestPrepare(resp)
+			apiErr, err := c.reportError(resp)
 			if err != nil {
-				return result, fmt.Errorf("cannot prepare bad request: %w", err)
+				return result, period, fmt.Errorf("cannot prepare bad request: %w", err)
 			}
-			return result, fmt.Errorf("ошибка отчета %s", data.Error.ErrorDetail)
+			if IsReportNameConflict(apiErr) && c.nameConflict == RenameOnConflict && renames < maxConflictRenames {
+				renames++
+				reportName = conflictName(baseName, renames)
+				m.ReportName = reportName
+				params.ReportName = reportName + fmt.Sprintf("_part_%d", part)
+				continue
+			}
+			return result, period, fmt.Errorf("ошибка отчета: %w", apiErr)
 		default:
-			return result, fmt.Errorf("cтатус код сервера при получении отчета %v", resp.StatusCode)
+			resp.Body.Close()
+			return result, period, fmt.Errorf("cтатус код сервера при получении отчета %v", resp.StatusCode)
 		}
 	}
 }
@@ -193,36 +372,61 @@ type Response struct {
 	} `json:"error"`
 }
 
-func (c *Client) createGetReportRequest(ctx context.Context, params statistics.ReportDefinition) (*http.Request, error) {
+func (c *Client) createGetReportRequest(ctx context.Context, params statistics.ReportDefinition, headers map[string]string) (*http.Request, error) {
 	reqContent := Request{Params: params}
 	body, err := json.Marshal(reqContent)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.direct.yandex.com/json/v5/reports", bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint("reports"), bytes.NewBuffer(body))
 	if err != nil {
 		return nil, err
 	}
 
-	c.buildHeader(req)
+	if err := c.buildHeader(req); err != nil {
+		return nil, err
+	}
+
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	// Ответ распаковывается в Client.do до middleware, поэтому они и обработка ошибок видят несжатое тело.
+	req.Header.Set("Accept-Encoding", "gzip")
 
 	return req, nil
 }
 
-func (c *Client) badRequestPrepare(resp *http.Response) (Response, error) {
+// reportHeaders заголовки запроса отчета, влияющие на его содержимое. Входят в ключ кэша отчетов.
+func (c *Client) reportHeaders() map[string]string {
+	return map[string]string{
+		"Accept-Language":   c.lang(),
+		"skipReportHeader":  "true",
+		"skipReportSummary": "true",
+	}
+}
+
+// reportError разбирает ошибку сервиса Reports из тела ответа.
+func (c *Client) reportError(resp *http.Response) (*APIError, error) {
+	defer resp.Body.Close()
+
 	responseBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return Response{}, fmt.Errorf("cant read response body: %w", err)
+		return nil, fmt.Errorf("cant read response body: %w", err)
 	}
 
-	var data Response
+	var data apiResponse
 
 	err = json.Unmarshal(responseBody, &data)
 	if err != nil {
-		return Response{}, fmt.Errorf("cant unmarshal response body: %w", err)
+		return nil, fmt.Errorf("cant unmarshal response body: %w", err)
+	}
+
+	if data.Error == nil {
+		return nil, fmt.Errorf("no error in response body")
 	}
 
-	return data, nil
+	return data.Error, nil
 }
 
 func (c *Client) waitInit(resp *http.Response) error {
@@ -230,14 +434,14 @@ func (c *Client) waitInit(resp *http.Response) error {
 		return fmt.Errorf("response is nil")
 	}
 
-	retryIn, err := strconv.Atoi(resp.Header.Get("retryIn"))
+	retryIn, err := strconv.Atoi(resp.Header.Get(HeaderRetryIn))
 	if err != nil {
 		return fmt.Errorf("retryIn: %w", err)
 	}
 
 	c.statisticsLimit.retryInterval = int32(retryIn)
 
-	reportsInQueue, err := strconv.Atoi(resp.Header.Get("reportsInQueue"))
+	reportsInQueue, err := strconv.Atoi(resp.Header.Get(HeaderReportsInQueue))
 	if err != nil {
 		return fmt.Errorf("reportsInQueue: %w", err)
 	}
@@ -257,24 +461,99 @@ func (c *Client) waitInfo(reportName string) {
 	}
 }
 
-func createTSVFile(dir string, filename string, resp *http.Response) (string, int, error) {
+// reportHeaderLines количество служебных строк в начале страницы отчета без строки с названием: заголовок с названиями столбцов.
+const reportHeaderLines = 1
+
+// savedPart сохраненная часть отчета.
+type savedPart struct {
+	path  string
+	lines int    // Количество строк отчета, включая заголовок.
+	size  int64  // Размер файла.
+	sum   string // SHA-256 файла.
+}
+
+// lineCounter считает строки записанного текста. Последняя строка без перевода строки тоже учитывается.
+type lineCounter struct {
+	n    int
+	last byte
+}
+
+func (c *lineCounter) Write(p []byte) (int, error) {
+	c.n += bytes.Count(p, []byte{'\n'})
+	if len(p) > 0 {
+		c.last = p[len(p)-1]
+	}
+
+	return len(p), nil
+}
+
+func (c *lineCounter) lines() int {
+	if c.last != 0 && c.last != '\n' {
+		return c.n + 1
+	}
 
+	return c.n
+}
+
+// countingWriter считает записанные байты.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+
+	return n, err //nolint:wrapcheck
+}
+
+func createTSVFile(dir string, filename string, resp *http.Response, comp Compression) (savedPart, error) {
 	if resp == nil {
-		return "", 0, fmt.Errorf("response is nil")
+		return savedPart{}, fmt.Errorf("response is nil")
 	}
 	defer resp.Body.Close()
 
-	f, err := os.CreateTemp(dir, fmt.Sprintf("%s_*.tsv", filename))
+	f, err := os.CreateTemp(dir, fmt.Sprintf("%s_*.tsv%s", filename, comp.Ext()))
+	if err != nil {
+		return savedPart{}, fmt.Errorf("failed to create file: %w", err)
+	}
+
+	saved, err := writePart(f, resp.Body, comp)
+	if closeErr := f.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("close: %w", closeErr)
+	}
+
 	if err != nil {
-		return "", 0, fmt.Errorf("failed to create file: %w", err)
+		// Недописанная часть не должна остаться в каталоге: Cleanup не отличил бы ее от загруженной.
+		_ = os.Remove(f.Name())
+
+		return savedPart{}, err
 	}
-	defer f.Close()
 
-	_, err = io.Copy(f, resp.Body)
+	saved.path = f.Name()
+
+	return saved, nil
+}
+
+// writePart сжимает body в f и считает строки, размер и контрольную сумму записанного файла.
+func writePart(f io.Writer, body io.Reader, comp Compression) (savedPart, error) {
+	h := sha256.New()
+	disk := &countingWriter{w: io.MultiWriter(f, h)}
+
+	w, err := compressWriter(disk, comp)
 	if err != nil {
-		return "", 0, err
+		return savedPart{}, err
+	}
+
+	lines := &lineCounter{}
+	if _, err := io.Copy(w, io.TeeReader(body, lines)); err != nil {
+		return savedPart{}, err
 	}
-	stat, _ := f.Stat()
-	size := stat.Size()
-	return f.Name(), int(size), nil
+
+	if err := w.Close(); err != nil {
+		return savedPart{}, fmt.Errorf("compress: %w", err)
+	}
+
+	return savedPart{lines: lines.lines(), size: disk.n, sum: hex.EncodeToString(h.Sum(nil))}, nil
 }
@@ -0,0 +1,144 @@
+package yandex_direct_sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mg-realcom/yandex-direct-sdk/clients"
+	"github.com/mg-realcom/yandex-direct-sdk/common"
+)
+
+var ErrClientNotFound = errors.New("client not found")
+
+// profileTimeout ограничивает запрос параметров аккаунта, который не зависит от отмены контекста отдельного вызова.
+const profileTimeout = 30 * time.Second
+
+// DefaultClientFields параметры аккаунта, запрашиваемые по умолчанию.
+func DefaultClientFields() []clients.FieldName {
+	return []clients.FieldName{
+		clients.FieldClientID,
+		clients.FieldLogin,
+		clients.FieldClientInfo,
+		clients.FieldCurrency,
+		clients.FieldGrants,
+		clients.FieldNotification,
+		clients.FieldRestrictions,
+		clients.FieldSettings,
+		clients.FieldTinInfo,
+	}
+}
+
+// accountCache кэш параметров аккаунта логина, которые не меняются между запросами отчетов.
+// Одновременные промахи ждут один запрос к API, блокировка на время запроса не удерживается.
+type accountCache struct {
+	mu       sync.Mutex
+	currency clients.Currency
+	inflight *currencyCall
+}
+
+type currencyCall struct {
+	done     chan struct{}
+	currency clients.Currency
+	err      error
+}
+
+// GetClient возвращает параметры аккаунта Client.Login.
+func (c *Client) GetClient(ctx context.Context, fields ...clients.FieldName) (clients.ClientGetItem, error) {
+	return c.getClient(ctx, c.Login, fields...)
+}
+
+func (c *Client) getClient(ctx context.Context, login string, fields ...clients.FieldName) (clients.ClientGetItem, error) {
+	if len(fields) == 0 {
+		fields = DefaultClientFields()
+	}
+
+	var result clients.GetResult
+	if err := c.call(ctx, login, "clients", "get", clients.GetParams{FieldNames: fields}, &result); err != nil {
+		return clients.ClientGetItem{}, fmt.Errorf("clients.get: %w", err)
+	}
+
+	if len(result.Clients) == 0 {
+		return clients.ClientGetItem{}, fmt.Errorf("clients.get %s: %w", login, ErrClientNotFound)
+	}
+
+	return result.Clients[0], nil
+}
+
+// UpdateClient изменяет параметры аккаунта Client.Login.
+func (c *Client) UpdateClient(ctx context.Context, item clients.ClientUpdateItem) (common.ActionResult, error) {
+	var result clients.UpdateResult
+	if err := c.Call(ctx, "clients", "update", clients.UpdateParams{Clients: []clients.ClientUpdateItem{item}}, &result); err != nil {
+		return common.ActionResult{}, fmt.Errorf("clients.update: %w", err)
+	}
+
+	if len(result.UpdateResults) == 0 {
+		return common.ActionResult{}, nil
+	}
+
+	return result.UpdateResults[0], nil
+}
+
+// Currency возвращает валюту аккаунта Client.Login. Значение запрашивается один раз и кэшируется.
+// Одновременные вызовы выполняют один запрос; отмена ctx прекращает только ожидание.
+func (c *Client) Currency(ctx context.Context) (clients.Currency, error) {
+	c.profile.mu.Lock()
+
+	if c.profile.currency != "" {
+		currency := c.profile.currency
+		c.profile.mu.Unlock()
+
+		return currency, nil
+	}
+
+	call := c.profile.inflight
+	if call == nil {
+		call = &currencyCall{done: make(chan struct{})}
+		c.profile.inflight = call
+		c.profile.mu.Unlock()
+
+		go c.fetchCurrency(call, c.Login)
+	} else {
+		c.profile.mu.Unlock()
+	}
+
+	select {
+	case <-call.done:
+		return call.currency, call.err
+	case <-ctx.Done():
+		return "", ctx.Err() //nolint:wrapcheck
+	}
+}
+
+func (c *Client) fetchCurrency(call *currencyCall, login string) {
+	ctx, cancel := context.WithTimeout(context.Background(), profileTimeout)
+	defer cancel()
+
+	item, err := c.getClient(ctx, login, clients.FieldCurrency)
+
+	c.profile.mu.Lock()
+	c.profile.inflight = nil
+
+	if err == nil && c.profile.currency == "" {
+		c.profile.currency = item.Currency
+	}
+
+	c.profile.mu.Unlock()
+
+	call.currency, call.err = item.Currency, err
+	close(call.done)
+}
+
+// setCurrency запоминает валюту аккаунта, уже полученную другим запросом.
+func (a *accountCache) setCurrency(currency clients.Currency) {
+	if currency == "" {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.currency = currency
+}
@@ -0,0 +1,238 @@
+package clients
+
+import (
+	"github.com/mg-realcom/yandex-direct-sdk/common"
+)
+
+type GetParams struct {
+	FieldNames []FieldName `json:"FieldNames"` // Имена параметров, которые требуется получить.
+}
+
+type GetResult struct {
+	Clients []ClientGetItem `json:"Clients"`
+}
+
+type ClientGetItem struct {
+	AccountQuality        *float64               `json:"AccountQuality,omitempty"`        // Качество аккаунта.
+	Archived              common.YesNo           `json:"Archived,omitempty"`              // Признак того, что клиент находится в архиве.
+	ClientID              int64                  `json:"ClientId,omitempty"`              // Идентификатор клиента.
+	ClientInfo            string                 `json:"ClientInfo,omitempty"`            // ФИО или название клиента.
+	CountryID             int                    `json:"CountryId,omitempty"`             // Идентификатор страны клиента из справочника регионов.
+	CreatedAt             string                 `json:"CreatedAt,omitempty"`             // Дата регистрации клиента в Директе, YYYY-MM-DD.
+	Currency              Currency               `json:"Currency,omitempty"`              // Валюта клиента.
+	Grants                []GrantGetItem         `json:"Grants,omitempty"`                // Полномочия клиента по управлению кампаниями.
+	Login                 string                 `json:"Login,omitempty"`                 // Логин клиента.
+	Notification          *Notification          `json:"Notification,omitempty"`          // Настройки уведомлений.
+	OverdraftSumAvailable *int64                 `json:"OverdraftSumAvailable,omitempty"` // Доступный кредитный лимит, умноженный на 1 000 000.
+	Phone                 string                 `json:"Phone,omitempty"`                 // Номер телефона клиента.
+	Representatives       []Representative       `json:"Representatives,omitempty"`       // Представители клиента.
+	Restrictions          []Restriction          `json:"Restrictions,omitempty"`          // Ограничения на ресурсы клиента.
+	Settings              []ClientSettingGetItem `json:"Settings,omitempty"`              // Настройки клиента.
+	Type                  ClientType             `json:"Type,omitempty"`                  // Тип клиента.
+	VatRate               *float64               `json:"VatRate,omitempty"`               // Ставка НДС.
+	TinInfo               *TinInfo               `json:"TinInfo,omitempty"`               // Сведения об ИНН клиента.
+}
+
+// Restriction возвращает значение ограничения element.
+func (c ClientGetItem) Restriction(element RestrictionElement) (int64, bool) {
+	for _, r := range c.Restrictions {
+		if r.Element == element {
+			return r.Value, true
+		}
+	}
+
+	return 0, false
+}
+
+// Setting возвращает значение настройки option.
+func (c ClientGetItem) Setting(option SettingOption) (common.YesNo, bool) {
+	for _, s := range c.Settings {
+		if s.Option == option {
+			return s.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// HasGrant сообщает, есть ли у клиента полномочие privilege.
+func (c ClientGetItem) HasGrant(privilege Privilege) bool {
+	for _, g := range c.Grants {
+		if g.Privilege == privilege {
+			return g.Value == common.YES
+		}
+	}
+
+	return false
+}
+
+type UpdateParams struct {
+	Clients []ClientUpdateItem `json:"Clients"`
+}
+
+type ClientUpdateItem struct {
+	ClientInfo   string                    `json:"ClientInfo,omitempty"`   // ФИО или название клиента.
+	Notification *NotificationUpdate       `json:"Notification,omitempty"` // Настройки уведомлений.
+	Phone        string                    `json:"Phone,omitempty"`        // Номер телефона клиента.
+	Settings     []ClientSettingUpdateItem `json:"Settings,omitempty"`     // Настройки клиента.
+	TinInfo      *TinInfo                  `json:"TinInfo,omitempty"`      // Сведения об ИНН клиента.
+}
+
+type UpdateResult struct {
+	UpdateResults []common.ActionResult `json:"UpdateResults"`
+}
+
+type Currency string
+
+const (
+	CurrencyRUB      Currency = "RUB"       // Российский рубль.
+	CurrencyBYN      Currency = "BYN"       // Белорусский рубль.
+	CurrencyCHF      Currency = "CHF"       // Швейцарский франк.
+	CurrencyEUR      Currency = "EUR"       // Евро.
+	CurrencyKZT      Currency = "KZT"       // Казахстанский тенге.
+	CurrencyTRY      Currency = "TRY"       // Турецкая лира.
+	CurrencyUAH      Currency = "UAH"       // Украинская гривна.
+	CurrencyUSD      Currency = "USD"       // Доллар США.
+	CurrencyUZS      Currency = "UZS"       // Узбекский сум.
+	CurrencyYNDFixed Currency = "YND_FIXED" // Условная единица Директа.
+)
+
+type GrantGetItem struct {
+	Privilege Privilege    `json:"Privilege"`        // Полномочие.
+	Value     common.YesNo `json:"Value"`            // Есть ли у клиента полномочие.
+	Agency    string       `json:"Agency,omitempty"` // Название агентства, выдавшего полномочие.
+}
+
+type GrantItem struct {
+	Privilege Privilege    `json:"Privilege"`
+	Value     common.YesNo `json:"Value"`
+}
+
+type Privilege string
+
+const (
+	PrivilegeEditCampaigns Privilege = "EDIT_CAMPAIGNS" // Редактирование кампаний.
+	PrivilegeImportXLS     Privilege = "IMPORT_XLS"     // Управление кампаниями с помощью XLS/XLSX-файлов.
+	PrivilegeTransferMoney Privilege = "TRANSFER_MONEY" // Перенос средств между кампаниями.
+)
+
+type Restriction struct {
+	Element RestrictionElement `json:"Element"` // Ограничиваемый ресурс.
+	Value   int64              `json:"Value"`   // Значение ограничения.
+}
+
+type RestrictionElement string
+
+const (
+	RestrictionCampaignsTotalPerClient     RestrictionElement = "CAMPAIGNS_TOTAL_PER_CLIENT"      // Максимальное количество кампаний у клиента.
+	RestrictionCampaignsUnarchivePerClient RestrictionElement = "CAMPAIGNS_UNARCHIVE_PER_CLIENT"  // Максимальное количество кампаний, разархивируемых за сутки.
+	RestrictionAdGroupsTotalPerCampaign    RestrictionElement = "ADGROUPS_TOTAL_PER_CAMPAIGN"     // Максимальное количество групп в кампании.
+	RestrictionAdsTotalPerAdGroup          RestrictionElement = "ADS_TOTAL_PER_ADGROUP"           // Максимальное количество объявлений в группе.
+	RestrictionKeywordsTotalPerAdGroup     RestrictionElement = "KEYWORDS_TOTAL_PER_ADGROUP"      // Максимальное количество ключевых фраз в группе.
+	RestrictionAdExtensionsTotal           RestrictionElement = "AD_EXTENSIONS_TOTAL"             // Максимальное количество расширений у клиента.
+	RestrictionStatReportsTotalInQueue     RestrictionElement = "STAT_REPORTS_TOTAL_IN_QUEUE"     // Максимальное количество отчетов в очереди.
+	RestrictionForecastReportsTotalInQueue RestrictionElement = "FORECAST_REPORTS_TOTAL_IN_QUEUE" // Максимальное количество прогнозов бюджета в очереди.
+	RestrictionWordstatReportsTotalInQueue RestrictionElement = "WORDSTAT_REPORTS_TOTAL_IN_QUEUE" // Максимальное количество отчетов Wordstat в очереди.
+	RestrictionAPIPoints                   RestrictionElement = "API_POINTS"                      // Суточный лимит баллов API.
+	RestrictionGeneralDomainBlacklistSize  RestrictionElement = "GENERAL_DOMAIN_BLACKLIST_SIZE"   // Максимальный размер списка запрещенных площадок.
+	RestrictionVideoDomainBlacklistSize    RestrictionElement = "VIDEO_DOMAIN_BLACKLIST_SIZE"     // Максимальный размер списка запрещенных площадок для видео.
+)
+
+type ClientSettingGetItem struct {
+	Option SettingOption `json:"Option"`
+	Value  common.YesNo  `json:"Value"`
+}
+
+type ClientSettingAddItem struct {
+	Option SettingOption `json:"Option"`
+	Value  common.YesNo  `json:"Value"`
+}
+
+type ClientSettingUpdateItem struct {
+	Option SettingOption `json:"Option"`
+	Value  common.YesNo  `json:"Value"`
+}
+
+type SettingOption string
+
+const (
+	SettingCorrectTyposAutomatically SettingOption = "CORRECT_TYPOS_AUTOMATICALLY" // Автоматически исправлять ошибки и опечатки.
+	SettingDisplayStoreRating        SettingOption = "DISPLAY_STORE_RATING"        // Показывать рейтинг магазина из Яндекс Маркета.
+	SettingSharedAccountEnabled      SettingOption = "SHARED_ACCOUNT_ENABLED"      // Подключен общий счет. Только для чтения.
+)
+
+type Notification struct {
+	Lang               string              `json:"Lang,omitempty"`               // Язык уведомлений.
+	SMSPhoneNumber     string              `json:"SmsPhoneNumber,omitempty"`     // Номер телефона для SMS-уведомлений.
+	Email              string              `json:"Email,omitempty"`              // Адрес электронной почты для уведомлений.
+	EmailSubscriptions []EmailSubscription `json:"EmailSubscriptions,omitempty"` // Типы уведомлений по электронной почте.
+}
+
+type NotificationUpdate struct {
+	Lang               string              `json:"Lang,omitempty"`               // Язык уведомлений.
+	Email              string              `json:"Email,omitempty"`              // Адрес электронной почты для уведомлений.
+	EmailSubscriptions []EmailSubscription `json:"EmailSubscriptions,omitempty"` // Типы уведомлений по электронной почте.
+}
+
+type EmailSubscription struct {
+	Option EmailSubscriptionOption `json:"Option"`
+	Value  common.YesNo            `json:"Value"`
+}
+
+type EmailSubscriptionOption string
+
+const (
+	ReceiveRecommendations EmailSubscriptionOption = "RECEIVE_RECOMMENDATIONS" // Новости и рекомендации.
+	TrackManagedCampaigns  EmailSubscriptionOption = "TRACK_MANAGED_CAMPAIGNS" // Уведомления по кампаниям, которыми управляет клиент.
+	TrackPositionChanges   EmailSubscriptionOption = "TRACK_POSITION_CHANGES"  // Уведомления об изменении позиций объявлений.
+)
+
+type ClientType string
+
+const (
+	ClientTypeClient ClientType = "CLIENT" // Рекламодатель.
+	ClientTypeAgency ClientType = "AGENCY" // Агентство.
+)
+
+type Representative struct {
+	Email string `json:"Email"`
+	Login string `json:"Login"`
+	Role  string `json:"Role"`
+}
+
+type TinInfo struct {
+	TinType TinType `json:"TinType"` // Тип налогоплательщика.
+	Tin     string  `json:"Tin"`     // ИНН.
+}
+
+type TinType string
+
+const (
+	TinTypePhysical     TinType = "PHYSICAL"      // Физическое лицо.
+	TinTypeLegal        TinType = "LEGAL"         // Юридическое лицо.
+	TinTypeForeignLegal TinType = "FOREIGN_LEGAL" // Иностранное юридическое лицо.
+	TinTypeIndividual   TinType = "INDIVIDUAL"    // Индивидуальный предприниматель.
+)
+
+type FieldName string
+
+const (
+	FieldAccountQuality        FieldName = "AccountQuality"
+	FieldArchived              FieldName = "Archived"
+	FieldClientID              FieldName = "ClientId"
+	FieldClientInfo            FieldName = "ClientInfo"
+	FieldCountryID             FieldName = "CountryId"
+	FieldCreatedAt             FieldName = "CreatedAt"
+	FieldCurrency              FieldName = "Currency"
+	FieldGrants                FieldName = "Grants"
+	FieldLogin                 FieldName = "Login"
+	FieldNotification          FieldName = "Notification"
+	FieldOverdraftSumAvailable FieldName = "OverdraftSumAvailable"
+	FieldPhone                 FieldName = "Phone"
+	FieldRepresentatives       FieldName = "Representatives"
+	FieldRestrictions          FieldName = "Restrictions"
+	FieldSettings              FieldName = "Settings"
+	FieldType                  FieldName = "Type"
+	FieldVatRate               FieldName = "VatRate"
+	FieldTinInfo               FieldName = "TinInfo"
+)
@@ -0,0 +1,139 @@
+package yandex_direct_sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mg-realcom/yandex-direct-sdk/clients"
+)
+
+// currencyServer отвечает на clients.get валютой RUB с задержкой delay. Первые failures ответов — ошибки API.
+type currencyServer struct {
+	delay    time.Duration
+	release  chan struct{}
+	failures int32
+	calls    int32
+}
+
+func (s *currencyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	atomic.AddInt32(&s.calls, 1)
+
+	if s.release != nil {
+		<-s.release
+	}
+
+	time.Sleep(s.delay)
+
+	if atomic.AddInt32(&s.failures, -1) >= 0 {
+		fmt.Fprint(w, `{"error":{"error_code":1000,"error_string":"Server error"}}`)
+
+		return
+	}
+
+	fmt.Fprint(w, `{"result":{"Clients":[{"Currency":"RUB"}]}}`)
+}
+
+func newCurrencyClient(t *testing.T, srv *currencyServer) *Client {
+	t.Helper()
+
+	token := "token"
+	c := newOAuthClient(t, srv)
+	c.Token = &token
+
+	return c
+}
+
+func TestCurrencySingleFlight(t *testing.T) {
+	const callers = 20
+
+	srv := &currencyServer{delay: 50 * time.Millisecond}
+	c := newCurrencyClient(t, srv)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			currency, err := c.Currency(context.Background())
+			if err != nil || currency != "RUB" {
+				t.Errorf("Currency() = %q, %v; want RUB", currency, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if _, err := c.Currency(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls := atomic.LoadInt32(&srv.calls); calls != 1 {
+		t.Errorf("clients.get calls = %d; want 1", calls)
+	}
+}
+
+func TestCurrencyCancelDoesNotWait(t *testing.T) {
+	srv := &currencyServer{release: make(chan struct{})}
+	c := newCurrencyClient(t, srv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := c.Currency(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Currency() error = %v; want %v", err, context.DeadlineExceeded)
+	}
+
+	close(srv.release)
+
+	currency, err := c.Currency(context.Background())
+	if err != nil || currency != "RUB" {
+		t.Fatalf("Currency() = %q, %v; want RUB", currency, err)
+	}
+
+	if calls := atomic.LoadInt32(&srv.calls); calls != 1 {
+		t.Errorf("clients.get calls = %d; want 1, the canceled caller's request must be reused", calls)
+	}
+}
+
+func TestCurrencyErrorIsNotCached(t *testing.T) {
+	srv := &currencyServer{failures: 1}
+	c := newCurrencyClient(t, srv)
+
+	var apiErr *APIError
+	if _, err := c.Currency(context.Background()); !errors.As(err, &apiErr) {
+		t.Fatalf("Currency() error = %v; want APIError", err)
+	}
+
+	currency, err := c.Currency(context.Background())
+	if err != nil || currency != "RUB" {
+		t.Fatalf("Currency() = %q, %v; want RUB", currency, err)
+	}
+
+	if calls := atomic.LoadInt32(&srv.calls); calls != 2 {
+		t.Errorf("clients.get calls = %d; want 2", calls)
+	}
+}
+
+func TestCurrencySeeded(t *testing.T) {
+	srv := &currencyServer{}
+	c := newCurrencyClient(t, srv)
+	c.profile.setCurrency(clients.Currency("KZT"))
+
+	currency, err := c.Currency(context.Background())
+	if err != nil || currency != "KZT" {
+		t.Fatalf("Currency() = %q, %v; want KZT", currency, err)
+	}
+
+	if calls := atomic.LoadInt32(&srv.calls); calls != 0 {
+		t.Errorf("clients.get calls = %d; want 0", calls)
+	}
+}
@@ -12,3 +12,17 @@ type Format string
 const (
 	FormatTSV Format = "TSV"
 )
+
+// ExceptionNotification ошибка или предупреждение в результате операции.
+type ExceptionNotification struct {
+	Code    int    `json:"Code"`
+	Message string `json:"Message"`
+	Details string `json:"Details,omitempty"`
+}
+
+// ActionResult результат операции над объектом.
+type ActionResult struct {
+	ID       int64                   `json:"Id,omitempty"`
+	Warnings []ExceptionNotification `json:"Warnings,omitempty"`
+	Errors   []ExceptionNotification `json:"Errors,omitempty"`
+}
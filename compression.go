@@ -0,0 +1,153 @@
+package yandex_direct_sdk
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression сжатие файлов отчетов на диске.
+type Compression string
+
+const (
+	CompressionNone Compression = ""     // Без сжатия.
+	CompressionGzip Compression = "gzip" // Gzip, файлы *.tsv.gz.
+	CompressionZstd Compression = "zstd" // Zstandard, файлы *.tsv.zst.
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// Ext возвращает расширение, добавляемое к .tsv.
+func (c Compression) Ext() string {
+	switch c {
+	case CompressionGzip:
+		return ".gz"
+	case CompressionZstd:
+		return ".zst"
+	case CompressionNone:
+	}
+
+	return ""
+}
+
+// SetReportCompression задает сжатие файлов отчетов. OpenReport читает сжатые файлы прозрачно.
+func (c *Client) SetReportCompression(comp Compression) {
+	c.compression = comp
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// compressWriter возвращает писатель, сжимающий данные в w. Close завершает поток, но не закрывает w.
+func compressWriter(w io.Writer, comp Compression) (io.WriteCloser, error) {
+	switch comp {
+	case CompressionNone:
+		return nopWriteCloser{w}, nil
+	case CompressionGzip:
+		return gzip.NewWriter(w), nil
+	case CompressionZstd:
+		zw, err := zstd.NewWriter(w)
+		if err != nil {
+			return nil, fmt.Errorf("zstd writer: %w", err)
+		}
+
+		return zw, nil
+	}
+
+	return nil, fmt.Errorf("unknown compression %q", comp)
+}
+
+// decompressReader определяет сжатие по первым байтам r и возвращает распаковывающий читатель.
+func decompressReader(r *bufio.Reader) (io.ReadCloser, error) {
+	head, _ := r.Peek(len(zstdMagic))
+
+	switch {
+	case bytes.HasPrefix(head, gzipMagic):
+		gr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("gzip reader: %w", err)
+		}
+
+		return gr, nil
+	case bytes.HasPrefix(head, zstdMagic):
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("zstd reader: %w", err)
+		}
+
+		return zr.IOReadCloser(), nil
+	}
+
+	return io.NopCloser(r), nil
+}
+
+// decodeContentEncoding распаковывает тело ответа, сжатое сервером по Accept-Encoding: gzip.
+func decodeContentEncoding(resp *http.Response) error {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return nil
+	}
+
+	wire := &countingReader{r: resp.Body}
+
+	gr, err := gzip.NewReader(wire)
+	if err != nil {
+		return fmt.Errorf("gzip response: %w", err)
+	}
+
+	resp.Body = &gzipBody{Reader: gr, body: resp.Body, wire: wire}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+
+	return nil
+}
+
+// WireCounter реализует тело ответа, распакованное клиентом. WireBytes возвращает количество байт,
+// прочитанных из сети до распаковки.
+type WireCounter interface {
+	WireBytes() int64
+}
+
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+	wire *countingReader
+}
+
+func (b *gzipBody) WireBytes() int64 {
+	return b.wire.n
+}
+
+func (b *gzipBody) Close() error {
+	_ = b.Reader.Close()
+
+	return b.body.Close() //nolint:wrapcheck
+}
+
+// countingReader считает прочитанные байты.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+
+	return n, err //nolint:wrapcheck
+}
@@ -0,0 +1,178 @@
+package yandex_direct_sdk
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func gzipString(t *testing.T, s string) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.String()
+}
+
+func TestGzipDecodedBeforeMiddlewares(t *testing.T) {
+	const report = "Date\tCampaignId\tConversions_12345_LSC\n2024-01-01\t1\t2\n"
+
+	gzipped := http.Header{"Content-Encoding": {"gzip"}}
+
+	tests := []struct {
+		name string
+		resp stubResponse
+		body string
+		logs string
+	}{
+		{
+			name: "report",
+			resp: stubResponse{status: http.StatusOK, header: gzipped, body: gzipString(t, report)},
+			body: report,
+		},
+		{
+			name: "server error",
+			resp: stubResponse{status: http.StatusInternalServerError, header: gzipped, body: gzipString(t, "internal failure")},
+			body: "internal failure",
+			logs: "internal failure",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, logs := newLoggedClient(&stubTransport{responses: []stubResponse{tt.resp}})
+
+			var seen string
+
+			c.Use(func(next http.RoundTripper) http.RoundTripper {
+				return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+					resp, err := next.RoundTrip(req)
+					if err != nil {
+						return nil, err
+					}
+
+					body, _ := io.ReadAll(resp.Body)
+					seen = string(body)
+					resp.Body = io.NopCloser(bytes.NewReader(body))
+
+					return resp, nil
+				})
+			})
+
+			files, _ := c.GetFiles(context.Background(), t.TempDir(), testReportDefinition(2))
+
+			if seen != tt.body {
+				t.Errorf("middleware saw body %q; want %q", seen, tt.body)
+			}
+
+			if tt.logs != "" && !strings.Contains(logs.String(), tt.logs) {
+				t.Errorf("logs do not contain %q:\n%s", tt.logs, logs)
+			}
+
+			if tt.logs == "" && strings.Contains(logs.String(), "RESPONSE") {
+				t.Errorf("successful response was dumped:\n%s", logs)
+			}
+
+			if len(files) == 0 {
+				return
+			}
+
+			r, err := OpenReport(files[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+
+			row, err := r.Next()
+			if err != nil || strings.Join(row, "\t") != "2024-01-01\t1\t2" {
+				t.Errorf("first row = %v, %v", row, err)
+			}
+		})
+	}
+}
+
+func TestGetFilesCompression(t *testing.T) {
+	const limit = 2
+
+	tests := []struct {
+		name string
+		comp Compression
+		ext  string
+	}{
+		{name: "none", comp: CompressionNone, ext: ".tsv"},
+		{name: "gzip", comp: CompressionGzip, ext: ".tsv.gz"},
+		{name: "zstd", comp: CompressionZstd, ext: ".tsv.zst"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Две полные страницы и неполная третья: по числу строк сжатого файла определяется конец отчета.
+			rec := &bodyRecorder{next: &stubTransport{responses: []stubResponse{reportPage(limit), reportPage(limit), reportPage(1)}}}
+			c, _ := newLoggedClient(rec)
+			c.SetReportCompression(tt.comp)
+
+			files, err := c.GetFiles(context.Background(), t.TempDir(), testReportDefinition(limit))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(files) != 3 || len(rec.bodies) != 3 {
+				t.Fatalf("files = %d, requests = %d; want 3 pages", len(files), len(rec.bodies))
+			}
+
+			for i, body := range rec.bodies {
+				var req Request
+				if err := json.Unmarshal([]byte(body), &req); err != nil {
+					t.Fatal(err)
+				}
+
+				if req.Params.Page.Offset != i*limit {
+					t.Errorf("page %d offset = %d; want %d", i+1, req.Params.Page.Offset, i*limit)
+				}
+			}
+
+			for _, path := range files {
+				if !strings.HasSuffix(path, tt.ext) {
+					t.Errorf("file %s; want extension %s", path, tt.ext)
+				}
+			}
+
+			rows := 0
+
+			err = ReadReports(files, func(header, row []string) error {
+				if strings.Join(header, "\t") != "Date\tCampaignId\tConversions_12345_LSC" {
+					t.Errorf("header = %v", header)
+				}
+
+				if strings.Join(row, "\t") != "2024-01-01\t1\t2" {
+					t.Errorf("row = %v", row)
+				}
+
+				rows++
+
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if rows != 2*limit+1 {
+				t.Errorf("rows = %d; want %d", rows, 2*limit+1)
+			}
+		})
+	}
+}
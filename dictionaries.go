@@ -0,0 +1,147 @@
+package yandex_direct_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mg-realcom/yandex-direct-sdk/changes"
+	"github.com/mg-realcom/yandex-direct-sdk/dictionaries"
+)
+
+// GetDictionaries получает справочники из API.
+func (c *Client) GetDictionaries(ctx context.Context, names ...dictionaries.Name) (dictionaries.Result, error) {
+	if len(names) == 0 {
+		names = dictionaries.All()
+	}
+
+	var result dictionaries.Result
+	if err := c.Call(ctx, "dictionaries", "get", dictionaries.GetParams{DictionaryNames: names}, &result); err != nil {
+		return result, fmt.Errorf("dictionaries.get: %w", err)
+	}
+
+	return result, nil
+}
+
+// CachedDictionaries возвращает справочники из кэша. Справочник запрашивается из API, если его нет в кэше
+// или срок его актуальности истек и checkDictionaries сообщает об изменении. checkDictionaries отслеживает
+// только четыре справочника: GeoRegions, MetroStations, TimeZones и Interests. Остальные запрашиваются
+// заново по истечении срока.
+func (c *Client) CachedDictionaries(ctx context.Context, cache *dictionaries.Cache, names ...dictionaries.Name) (dictionaries.Result, error) {
+	if len(names) == 0 {
+		names = dictionaries.All()
+	}
+
+	lang := c.lang()
+	entries := make(map[dictionaries.Name]json.RawMessage, len(names))
+	checks := map[string]changes.CheckDictionariesResult{}
+
+	var stale []dictionaries.Name
+
+	for _, name := range names {
+		entry, ok, err := cache.Load(name, lang)
+		if err != nil {
+			return dictionaries.Result{}, err
+		}
+
+		if !ok {
+			stale = append(stale, name)
+
+			continue
+		}
+
+		if cache.Expired(entry) {
+			fresh, err := c.confirmDictionary(ctx, cache, entry, checks)
+			if err != nil {
+				return dictionaries.Result{}, err
+			}
+
+			if !fresh {
+				stale = append(stale, name)
+
+				continue
+			}
+		}
+
+		entries[name] = entry.Data
+	}
+
+	if len(stale) > 0 {
+		fetched, err := c.fetchDictionaries(ctx, cache, lang, stale)
+		if err != nil {
+			return dictionaries.Result{}, err
+		}
+
+		for name, data := range fetched {
+			entries[name] = data
+		}
+	}
+
+	return dictionaries.Merge(entries)
+}
+
+// confirmDictionary продлевает срок записи, если checkDictionaries не сообщает об изменении справочника.
+func (c *Client) confirmDictionary(ctx context.Context, cache *dictionaries.Cache, entry dictionaries.Entry, checks map[string]changes.CheckDictionariesResult) (bool, error) {
+	if _, tracked := (changes.CheckDictionariesResult{}).Changed(entry.Name); !tracked || entry.Timestamp == "" {
+		return false, nil
+	}
+
+	check, ok := checks[entry.Timestamp]
+	if !ok {
+		var err error
+
+		check, err = c.CheckDictionaries(ctx, entry.Timestamp)
+		if err != nil {
+			return false, err
+		}
+
+		checks[entry.Timestamp] = check
+	}
+
+	if changed, _ := check.Changed(entry.Name); changed {
+		return false, nil
+	}
+
+	entry.FetchedAt = time.Now()
+	entry.Timestamp = check.Timestamp
+
+	return true, cache.Store(entry)
+}
+
+func (c *Client) fetchDictionaries(ctx context.Context, cache *dictionaries.Cache, lang string, names []dictionaries.Name) (map[dictionaries.Name]json.RawMessage, error) {
+	result, err := c.GetDictionaries(ctx, names...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Время сервера запрашивается после справочников: записи кэша сверяются с моментом, когда данные уже получены.
+	check, err := c.CheckDictionaries(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := dictionaries.Split(result)
+	if err != nil {
+		return nil, err
+	}
+
+	fetched := make(map[dictionaries.Name]json.RawMessage, len(names))
+	now := time.Now()
+
+	for _, name := range names {
+		data, ok := raw[name]
+		if !ok {
+			data = json.RawMessage("[]")
+		}
+
+		entry := dictionaries.Entry{Name: name, Language: lang, FetchedAt: now, Timestamp: check.Timestamp, Data: data}
+		if err := cache.Store(entry); err != nil {
+			return nil, err
+		}
+
+		fetched[name] = data
+	}
+
+	return fetched, nil
+}
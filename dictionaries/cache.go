@@ -0,0 +1,121 @@
+package dictionaries
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mg-realcom/yandex-direct-sdk/internal/fsutil"
+)
+
+const (
+	DefaultTTL = 24 * time.Hour
+
+	filePerm = 0o644
+	dirPerm  = 0o755
+)
+
+// Cache хранит справочники на диске, по файлу на справочник и язык.
+type Cache struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+}
+
+// Entry справочник в кэше.
+type Entry struct {
+	Name      Name            `json:"name"`
+	Language  string          `json:"language"`
+	FetchedAt time.Time       `json:"fetched_at"` // Время последнего получения или подтверждения актуальности.
+	Timestamp string          `json:"timestamp"`  // Время сервера для checkDictionaries на момент получения.
+	Data      json.RawMessage `json:"data"`
+}
+
+// NewCache создает кэш в каталоге dir. Нулевой ttl заменяется на DefaultTTL.
+func NewCache(dir string, ttl time.Duration) (*Cache, error) {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+
+	if err := os.MkdirAll(dir, dirPerm); err != nil {
+		return nil, fmt.Errorf("create cache dir: %w", err)
+	}
+
+	return &Cache{dir: dir, ttl: ttl, now: time.Now}, nil
+}
+
+// Expired сообщает, что срок актуальности записи истек и ее нужно проверить.
+func (c *Cache) Expired(e Entry) bool {
+	return c.now().Sub(e.FetchedAt) > c.ttl
+}
+
+func (c *Cache) path(name Name, lang string) string {
+	return filepath.Join(c.dir, fmt.Sprintf("%s_%s.json", name, lang))
+}
+
+// Load возвращает запись справочника. ok=false, если записи нет.
+func (c *Cache) Load(name Name, lang string) (Entry, bool, error) {
+	data, err := os.ReadFile(c.path(name, lang))
+	if errors.Is(err, os.ErrNotExist) {
+		return Entry{}, false, nil
+	}
+
+	if err != nil {
+		return Entry{}, false, fmt.Errorf("read %s: %w", name, err)
+	}
+
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		// Поврежденная запись считается отсутствующей и будет перезаписана.
+		return Entry{}, false, nil //nolint:nilerr
+	}
+
+	return e, true, nil
+}
+
+// Store сохраняет запись справочника атомарно.
+func (c *Cache) Store(e Entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", e.Name, err)
+	}
+
+	if err := fsutil.WriteFile(c.path(e.Name, e.Language), ".dictionary_*", data, filePerm); err != nil {
+		return fmt.Errorf("save %s: %w", e.Name, err)
+	}
+
+	return nil
+}
+
+// Split разбирает результат на записи по справочникам.
+func Split(result Result) (map[Name]json.RawMessage, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("marshal result: %w", err)
+	}
+
+	var raw map[Name]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("unmarshal result: %w", err)
+	}
+
+	return raw, nil
+}
+
+// Merge собирает результат из записей по справочникам.
+func Merge(entries map[Name]json.RawMessage) (Result, error) {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return Result{}, fmt.Errorf("marshal entries: %w", err)
+	}
+
+	var result Result
+	if err := json.Unmarshal(data, &result); err != nil {
+		return Result{}, fmt.Errorf("unmarshal entries: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,80 @@
+package dictionaries
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCacheExpired(t *testing.T) {
+	fetched := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		want    bool
+	}{
+		{name: "fresh", elapsed: time.Hour},
+		{name: "at ttl", elapsed: DefaultTTL},
+		{name: "expired", elapsed: DefaultTTL + time.Second, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewCache(t.TempDir(), 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			c.now = func() time.Time { return fetched.Add(tt.elapsed) }
+
+			if got := c.Expired(Entry{FetchedAt: fetched}); got != tt.want {
+				t.Errorf("Expired() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCacheStoreLoad(t *testing.T) {
+	c, err := NewCache(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok, err := c.Load(GeoRegions, "ru"); ok || err != nil {
+		t.Fatalf("Load() on empty cache = %v, %v; want no entry", ok, err)
+	}
+
+	entry := Entry{
+		Name:      GeoRegions,
+		Language:  "ru",
+		FetchedAt: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		Timestamp: "2024-03-01T12:00:00Z",
+		Data:      json.RawMessage(`[{"GeoRegionId":225}]`),
+	}
+	if err := c.Store(entry); err != nil {
+		t.Fatal(err)
+	}
+
+	got, ok, err := c.Load(GeoRegions, "ru")
+	if err != nil || !ok {
+		t.Fatalf("Load() = %v, %v", ok, err)
+	}
+
+	if !got.FetchedAt.Equal(entry.FetchedAt) || got.Timestamp != entry.Timestamp || string(got.Data) != string(entry.Data) {
+		t.Errorf("Load() = %+v; want %+v", got, entry)
+	}
+
+	if _, ok, _ := c.Load(GeoRegions, "en"); ok {
+		t.Error("entry is shared between languages")
+	}
+
+	if err := os.WriteFile(c.path(GeoRegions, "ru"), []byte("{broken"), filePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok, err := c.Load(GeoRegions, "ru"); ok || err != nil {
+		t.Errorf("Load() of a corrupted entry = %v, %v; want no entry", ok, err)
+	}
+}
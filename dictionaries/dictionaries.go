@@ -0,0 +1,112 @@
+package dictionaries
+
+import (
+	"github.com/mg-realcom/yandex-direct-sdk/common"
+)
+
+type Name string
+
+const (
+	GeoRegions              Name = "GeoRegions"              // Регионы.
+	Currencies              Name = "Currencies"              // Валюты и ограничения в них.
+	TimeZones               Name = "TimeZones"               // Часовые пояса.
+	Constants               Name = "Constants"               // Ограничения на значения параметров.
+	AdCategories            Name = "AdCategories"            // Особые категории рекламируемых товаров и услуг.
+	OperationSystemVersions Name = "OperationSystemVersions" // Версии операционных систем.
+	SupplySidePlatforms     Name = "SupplySidePlatforms"     // Внешние сети (SSP).
+	Interests               Name = "Interests"               // Интересы к категориям мобильных приложений.
+	AudienceCriteriaTypes   Name = "AudienceCriteriaTypes"   // Социально-демографические характеристики и поведенческие признаки.
+	MetroStations           Name = "MetroStations"           // Станции метрополитена.
+)
+
+// All все поддерживаемые справочники.
+func All() []Name {
+	return []Name{
+		GeoRegions, Currencies, TimeZones, Constants, AdCategories, OperationSystemVersions,
+		SupplySidePlatforms, Interests, AudienceCriteriaTypes, MetroStations,
+	}
+}
+
+type GetParams struct {
+	DictionaryNames []Name `json:"DictionaryNames"`
+}
+
+// Result справочники. Заполнены только запрошенные.
+type Result struct {
+	GeoRegions              []GeoRegionsItem              `json:"GeoRegions,omitempty"`
+	Currencies              []CurrenciesItem              `json:"Currencies,omitempty"`
+	TimeZones               []TimeZonesItem               `json:"TimeZones,omitempty"`
+	Constants               []ConstantsItem               `json:"Constants,omitempty"`
+	AdCategories            []AdCategoriesItem            `json:"AdCategories,omitempty"`
+	OperationSystemVersions []OperationSystemVersionsItem `json:"OperationSystemVersions,omitempty"`
+	SupplySidePlatforms     []SupplySidePlatformsItem     `json:"SupplySidePlatforms,omitempty"`
+	Interests               []InterestsItem               `json:"Interests,omitempty"`
+	AudienceCriteriaTypes   []AudienceCriteriaTypesItem   `json:"AudienceCriteriaTypes,omitempty"`
+	MetroStations           []MetroStationsItem           `json:"MetroStations,omitempty"`
+}
+
+type GeoRegionsItem struct {
+	GeoRegionID   int64  `json:"GeoRegionId"`   // Идентификатор региона.
+	GeoRegionName string `json:"GeoRegionName"` // Название региона на языке запроса.
+	GeoRegionType string `json:"GeoRegionType"` // Тип региона: World, Continent, Country, Administrative area, City и т. д.
+	ParentID      *int64 `json:"ParentId"`      // Идентификатор родительского региона.
+}
+
+type CurrenciesItem struct {
+	Currency   string          `json:"Currency"`   // Код валюты.
+	Properties []ConstantsItem `json:"Properties"` // Ограничения в валюте.
+}
+
+type TimeZonesItem struct {
+	TimeZone     string `json:"TimeZone"`     // Код часового пояса.
+	TimeZoneName string `json:"TimeZoneName"` // Название часового пояса.
+	UtcOffset    int    `json:"UtcOffset"`    // Смещение от UTC в секундах.
+}
+
+type ConstantsItem struct {
+	Name  string `json:"Name"`
+	Value string `json:"Value"`
+}
+
+type AdCategoriesItem struct {
+	AdCategory  string `json:"AdCategory"`  // Категория.
+	Description string `json:"Description"` // Описание категории.
+	Message     string `json:"Message"`     // Предупреждение, показываемое в объявлении.
+}
+
+type OperationSystemVersionsItem struct {
+	OsName    string `json:"OsName"`
+	OsVersion string `json:"OsVersion"`
+}
+
+type SupplySidePlatformsItem struct {
+	Title string `json:"Title"`
+}
+
+type InterestsItem struct {
+	InterestID   int64        `json:"InterestId"`
+	ParentID     *int64       `json:"ParentId"`
+	Name         string       `json:"Name"`
+	IsTargetable common.YesNo `json:"IsTargetable"`
+}
+
+type AudienceCriteriaTypesItem struct {
+	Type         string                       `json:"Type"`
+	BlockElement string                       `json:"BlockElement"`
+	Name         string                       `json:"Name"`
+	Description  string                       `json:"Description"`
+	CanSelect    string                       `json:"CanSelect"`
+	Values       []AudienceCriteriaValuesItem `json:"Values"`
+}
+
+type AudienceCriteriaValuesItem struct {
+	ID          string `json:"Id"`
+	Name        string `json:"Name"`
+	Description string `json:"Description"`
+}
+
+type MetroStationsItem struct {
+	GeoRegionID      int64  `json:"GeoRegionId"`
+	MetroStationID   int64  `json:"MetroStationId"`
+	MetroStationName string `json:"MetroStationName"`
+}
@@ -0,0 +1,148 @@
+package yandex_direct_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mg-realcom/yandex-direct-sdk/common"
+	"github.com/mg-realcom/yandex-direct-sdk/dictionaries"
+)
+
+// dictionariesServer отвечает на dictionaries.get и changes.checkDictionaries и записывает вызовы.
+// Время сервера увеличивается с каждой проверкой.
+type dictionariesServer struct {
+	regionsChanged common.YesNo
+	checks         int
+	calls          []string
+}
+
+func (s *dictionariesServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Method string `json:"method"`
+		Params struct {
+			DictionaryNames []string `json:"DictionaryNames"`
+			Timestamp       string   `json:"Timestamp"`
+		} `json:"params"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	switch req.Method {
+	case "get":
+		s.calls = append(s.calls, "get "+strings.Join(req.Params.DictionaryNames, ","))
+		fmt.Fprint(w, `{"result":{"GeoRegions":[{"GeoRegionId":225}],"Currencies":[{"Currency":"RUB"}]}}`)
+	case "checkDictionaries":
+		s.checks++
+		s.calls = append(s.calls, "check "+req.Params.Timestamp)
+
+		changed := common.NO
+		if req.Params.Timestamp != "" {
+			changed = s.regionsChanged
+		}
+
+		fmt.Fprintf(w, `{"result":{"RegionsChanged":%q,"Timestamp":"ts-%d"}}`, changed, s.checks)
+	}
+}
+
+// ageEntries сдвигает время получения записей кэша на age назад.
+func ageEntries(t *testing.T, cache *dictionaries.Cache, age time.Duration, names ...dictionaries.Name) {
+	t.Helper()
+
+	for _, name := range names {
+		entry, ok, err := cache.Load(name, defaultLanguage)
+		if err != nil || !ok {
+			t.Fatalf("Load(%s) = %v, %v", name, ok, err)
+		}
+
+		entry.FetchedAt = entry.FetchedAt.Add(-age)
+		if err := cache.Store(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCachedDictionaries(t *testing.T) {
+	names := []dictionaries.Name{dictionaries.GeoRegions, dictionaries.Currencies}
+
+	tests := []struct {
+		name    string
+		age     time.Duration
+		changed common.YesNo
+		calls   []string
+	}{
+		{name: "fresh", age: time.Hour},
+		{
+			name:    "expired and unchanged",
+			age:     2 * dictionaries.DefaultTTL,
+			changed: common.NO,
+			calls:   []string{"check ts-1", "get Currencies", "check "},
+		},
+		{
+			name:    "expired and changed",
+			age:     2 * dictionaries.DefaultTTL,
+			changed: common.YES,
+			calls:   []string{"check ts-1", "get GeoRegions,Currencies", "check "},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &dictionariesServer{regionsChanged: tt.changed}
+			token := "token"
+			c := newOAuthClient(t, srv)
+			c.Token = &token
+
+			cache, err := dictionaries.NewCache(t.TempDir(), 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := c.CachedDictionaries(context.Background(), cache, names...); err != nil {
+				t.Fatal(err)
+			}
+
+			// Время сервера запрашивается после самих справочников.
+			if want := []string{"get GeoRegions,Currencies", "check "}; fmt.Sprint(srv.calls) != fmt.Sprint(want) {
+				t.Fatalf("first load calls = %q; want %q", srv.calls, want)
+			}
+
+			srv.calls = nil
+			ageEntries(t, cache, tt.age, names...)
+
+			result, err := c.CachedDictionaries(context.Background(), cache, names...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if fmt.Sprint(srv.calls) != fmt.Sprint(tt.calls) {
+				t.Errorf("calls = %q; want %q", srv.calls, tt.calls)
+			}
+
+			if len(result.GeoRegions) != 1 || len(result.Currencies) != 1 {
+				t.Errorf("result = %+v", result)
+			}
+
+			entry, _, err := cache.Load(dictionaries.GeoRegions, defaultLanguage)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if cache.Expired(entry) {
+				t.Error("GeoRegions entry is still expired after revalidation")
+			}
+
+			if tt.calls != nil && entry.Timestamp == "ts-1" {
+				t.Errorf("GeoRegions timestamp = %q; want the timestamp of the last check", entry.Timestamp)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package yandex_direct_sdk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mg-realcom/yandex-direct-sdk/clients"
+	"github.com/mg-realcom/yandex-direct-sdk/common"
+	"github.com/mg-realcom/yandex-direct-sdk/statistics"
+)
+
+// DefaultFanOutConcurrency количество логинов, отчеты по которым запрашиваются одновременно по умолчанию.
+const DefaultFanOutConcurrency = 5
+
+// ReportTemplate шаблон отчета для нескольких логинов. ReportName определения используется как префикс
+// имени отчета, само имя строит Client.ReportName клиента логина.
+type ReportTemplate struct {
+	Dir          string
+	Definition   statistics.ReportDefinition
+	WithCurrency bool // Дополнить результат валютой аккаунта, в которой указаны денежные поля отчета.
+}
+
+// FanOutOptions параметры запроса отчетов по нескольким логинам.
+type FanOutOptions struct {
+	Concurrency int // Количество логинов, обрабатываемых одновременно. По умолчанию DefaultFanOutConcurrency.
+}
+
+// LoginResult результат получения отчета по одному логину.
+type LoginResult struct {
+	Login    string
+	Files    []string
+	Currency clients.Currency // Валюта аккаунта, если в шаблоне задан WithCurrency.
+	Err      error
+	Duration time.Duration
+}
+
+// FanOutResult результаты по логинам в порядке, в котором логины были переданы.
+type FanOutResult []LoginResult
+
+// Failed возвращает результаты с ошибкой.
+func (r FanOutResult) Failed() FanOutResult {
+	var out FanOutResult
+
+	for _, res := range r {
+		if res.Err != nil {
+			out = append(out, res)
+		}
+	}
+
+	return out
+}
+
+// Succeeded возвращает успешные результаты.
+func (r FanOutResult) Succeeded() FanOutResult {
+	var out FanOutResult
+
+	for _, res := range r {
+		if res.Err == nil {
+			out = append(out, res)
+		}
+	}
+
+	return out
+}
+
+// Err возвращает сводную ошибку по всем логинам с ошибкой или nil.
+func (r FanOutResult) Err() error {
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+
+	msgs := make([]string, 0, len(failed))
+	for _, res := range failed {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", res.Login, res.Err))
+	}
+
+	return &FanOutError{Result: failed, msg: strings.Join(msgs, "; ")}
+}
+
+// FanOutError ошибка получения отчетов по части логинов.
+type FanOutError struct {
+	Result FanOutResult
+	msg    string
+}
+
+func (e *FanOutError) Error() string {
+	return fmt.Sprintf("отчеты не получены для %d логинов: %s", len(e.Result), e.msg)
+}
+
+// GetReports получает отчет по шаблону для каждого логина. Ошибка по одному логину не прерывает остальные.
+func (c *Client) GetReports(ctx context.Context, logins []string, tpl ReportTemplate, opts FanOutOptions) FanOutResult {
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = DefaultFanOutConcurrency
+	}
+
+	result := make(FanOutResult, len(logins))
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+
+	for i, login := range logins {
+		result[i].Login = login
+
+		select {
+		case <-ctx.Done():
+			result[i].Err = ctx.Err()
+
+			continue
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+
+		go func(res *LoginResult) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			started := time.Now()
+			defer func() { res.Duration = time.Since(started) }()
+
+			sub := c.WithLogin(res.Login)
+
+			res.Files, res.Err = sub.GetFiles(ctx, tpl.Dir, tpl.definitionFor(sub))
+			if res.Err != nil || !tpl.WithCurrency {
+				return
+			}
+
+			if res.Currency, res.Err = sub.Currency(ctx); res.Err != nil {
+				res.Err = fmt.Errorf("currency: %w", res.Err)
+			}
+		}(&result[i])
+	}
+
+	wg.Wait()
+
+	return result
+}
+
+// definitionFor копирует определение для клиента логина. Page копируется, так как GetFiles изменяет смещение.
+func (t ReportTemplate) definitionFor(sub *Client) statistics.ReportDefinition {
+	def := t.Definition
+
+	if def.Page != nil {
+		page := *def.Page
+		def.Page = &page
+	} else {
+		def.Page = &common.Page{Limit: statistics.DefaultPageLimit}
+	}
+
+	def.ReportName = sub.ReportName(t.Definition.ReportName, def)
+
+	return def
+}
+
+// GetReports получает отчет по шаблону для каждого клиента агентства.
+func (a *Agency) GetReports(ctx context.Context, archived *common.YesNo, tpl ReportTemplate, opts FanOutOptions) (FanOutResult, error) {
+	logins, err := a.Logins(ctx, archived)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.client.GetReports(ctx, logins, tpl, opts), nil
+}
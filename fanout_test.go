@@ -0,0 +1,53 @@
+package yandex_direct_sdk
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mg-realcom/yandex-direct-sdk/common"
+	"github.com/mg-realcom/yandex-direct-sdk/statistics"
+)
+
+func TestReportTemplateDefinitionFor(t *testing.T) {
+	tpl := ReportTemplate{Definition: statistics.ReportDefinition{
+		FieldNames:    []string{"Date", "CampaignId"},
+		Page:          &common.Page{Limit: 100},
+		ReportName:    "test",
+		ReportType:    statistics.CampaignPerformanceReport,
+		DateRangeType: statistics.DateRangeAuto,
+		Format:        common.FormatTSV,
+	}}
+	client := &Client{}
+
+	tests := []struct {
+		name  string
+		login string
+	}{
+		{name: "first login", login: "first"},
+		{name: "second login", login: "second"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := client.WithLogin(tt.login)
+			def := tpl.definitionFor(sub)
+
+			if want := sub.ReportName(tpl.Definition.ReportName, def); def.ReportName != want {
+				t.Errorf("ReportName = %q, want %q", def.ReportName, want)
+			}
+
+			if !strings.HasPrefix(def.ReportName, tt.login+"_test_") {
+				t.Errorf("ReportName = %q, want prefix %q", def.ReportName, tt.login+"_test_")
+			}
+
+			if def.Page == tpl.Definition.Page {
+				t.Error("Page is shared with template")
+			}
+		})
+	}
+
+	tpl.Definition.Page = nil
+	if def := tpl.definitionFor(client.WithLogin("first")); def.Page == nil || def.Page.Limit != statistics.DefaultPageLimit {
+		t.Errorf("Page = %+v, want limit %d", def.Page, statistics.DefaultPageLimit)
+	}
+}
@@ -0,0 +1,34 @@
+package geo
+
+import (
+	"context"
+	"fmt"
+
+	sdk "github.com/mg-realcom/yandex-direct-sdk"
+	"github.com/mg-realcom/yandex-direct-sdk/dictionaries"
+)
+
+// Load строит дерево регионов по справочнику GeoRegions из кэша, с названиями на языках langs.
+// По умолчанию загружаются русские и английские названия.
+func Load(ctx context.Context, client *sdk.Client, cache *dictionaries.Cache, langs ...string) (*Tree, error) {
+	if len(langs) == 0 {
+		langs = []string{"ru", "en"}
+	}
+
+	var tree *Tree
+
+	for _, lang := range langs {
+		result, err := client.WithLanguage(lang).CachedDictionaries(ctx, cache, dictionaries.GeoRegions)
+		if err != nil {
+			return nil, fmt.Errorf("GeoRegions (%s): %w", lang, err)
+		}
+
+		if tree == nil {
+			tree = NewTree(lang, result.GeoRegions)
+		} else {
+			tree.AddNames(lang, result.GeoRegions)
+		}
+	}
+
+	return tree, nil
+}
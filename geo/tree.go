@@ -0,0 +1,489 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mg-realcom/yandex-direct-sdk/dictionaries"
+	"github.com/mg-realcom/yandex-direct-sdk/internal/idutil"
+)
+
+var (
+	ErrUnknownRegion   = errors.New("unknown region")
+	ErrOrphanNegative  = errors.New("negative region is not inside any targeted region")
+	ErrDuplicateRegion = errors.New("region is listed more than once")
+	ErrAmbiguousName   = errors.New("region name is ambiguous")
+)
+
+// Region регион из справочника GeoRegions.
+type Region struct {
+	ID        int64
+	ParentID  int64 // 0, если региона-родителя нет.
+	HasParent bool
+	Type      string
+	Names     map[string]string // Название по языку: ru, en и т. д.
+}
+
+// Tree дерево регионов с поиском по названию и операциями над списками регионов групп объявлений.
+// В списке регионов положительный идентификатор включает регион и все вложенные, отрицательный — исключает.
+type Tree struct {
+	regions  map[int64]*Region
+	children map[int64][]int64
+	roots    []int64
+	byName   map[string][]int64
+}
+
+// NewTree строит дерево по справочнику GeoRegions, полученному на языке lang.
+func NewTree(lang string, items []dictionaries.GeoRegionsItem) *Tree {
+	t := &Tree{
+		regions:  make(map[int64]*Region, len(items)),
+		children: map[int64][]int64{},
+		byName:   map[string][]int64{},
+	}
+
+	for _, item := range items {
+		r := &Region{ID: item.GeoRegionID, Type: item.GeoRegionType, Names: map[string]string{}}
+		if item.ParentID != nil {
+			r.ParentID, r.HasParent = *item.ParentID, true
+		}
+
+		t.regions[r.ID] = r
+	}
+
+	t.breakCycles()
+
+	for _, r := range t.regions {
+		if _, ok := t.regions[r.ParentID]; r.HasParent && ok && r.ParentID != r.ID {
+			t.children[r.ParentID] = append(t.children[r.ParentID], r.ID)
+		} else {
+			r.HasParent = false
+			t.roots = append(t.roots, r.ID)
+		}
+	}
+
+	for id := range t.children {
+		idutil.Sort(t.children[id])
+	}
+
+	idutil.Sort(t.roots)
+	t.AddNames(lang, items)
+
+	return t
+}
+
+// breakCycles отрывает от родителя регион, замыкающий цикл в справочнике, и делает его корнем.
+func (t *Tree) breakCycles() {
+	all := make([]int64, 0, len(t.regions))
+	for id := range t.regions {
+		all = append(all, id)
+	}
+
+	idutil.Sort(all)
+
+	for _, id := range all {
+		visited := map[int64]struct{}{}
+
+		for r, ok := t.regions[id]; ok && r.HasParent; r, ok = t.regions[r.ParentID] {
+			if _, seen := visited[r.ID]; seen {
+				break
+			}
+
+			visited[r.ID] = struct{}{}
+
+			if r.ParentID == id {
+				t.regions[id].HasParent = false
+
+				break
+			}
+		}
+	}
+}
+
+// AddNames добавляет названия регионов на языке lang из справочника, полученного на этом языке.
+func (t *Tree) AddNames(lang string, items []dictionaries.GeoRegionsItem) {
+	for _, item := range items {
+		r, ok := t.regions[item.GeoRegionID]
+		if !ok || item.GeoRegionName == "" {
+			continue
+		}
+
+		r.Names[lang] = item.GeoRegionName
+		key := normalizeName(item.GeoRegionName)
+
+		if !containsID(t.byName[key], r.ID) {
+			t.byName[key] = append(t.byName[key], r.ID)
+		}
+	}
+}
+
+// Region возвращает регион по идентификатору.
+func (t *Tree) Region(id int64) (Region, bool) {
+	r, ok := t.regions[id]
+	if !ok {
+		return Region{}, false
+	}
+
+	return *r, true
+}
+
+// Name возвращает название региона на языке lang или на любом известном языке.
+func (t *Tree) Name(id int64, lang string) string {
+	r, ok := t.regions[id]
+	if !ok {
+		return strconv.FormatInt(id, 10)
+	}
+
+	if name, ok := r.Names[lang]; ok {
+		return name
+	}
+
+	for _, l := range []string{"ru", "en"} {
+		if name, ok := r.Names[l]; ok {
+			return name
+		}
+	}
+
+	for _, name := range r.Names {
+		return name
+	}
+
+	return strconv.FormatInt(id, 10)
+}
+
+// Lookup ищет регионы по названию на любом загруженном языке без учета регистра и различия «е» и «ё».
+func (t *Tree) Lookup(name string) []Region {
+	ids := t.byName[normalizeName(name)]
+	out := make([]Region, 0, len(ids))
+
+	for _, id := range ids {
+		out = append(out, *t.regions[id])
+	}
+
+	return out
+}
+
+// LookupOne ищет единственный регион по названию.
+func (t *Tree) LookupOne(name string) (Region, error) {
+	found := t.Lookup(name)
+
+	switch len(found) {
+	case 0:
+		return Region{}, fmt.Errorf("%w: %q", ErrUnknownRegion, name)
+	case 1:
+		return found[0], nil
+	default:
+		return Region{}, fmt.Errorf("%w: %q matches %d regions", ErrAmbiguousName, name, len(found))
+	}
+}
+
+// Children возвращает идентификаторы регионов, непосредственно вложенных в id.
+func (t *Tree) Children(id int64) []int64 {
+	return append([]int64(nil), t.children[id]...)
+}
+
+// Descendants возвращает идентификаторы всех регионов, вложенных в id.
+// Циклы в справочнике не приводят к зацикливанию: каждый регион посещается один раз.
+func (t *Tree) Descendants(id int64) []int64 {
+	var out []int64
+
+	visited := map[int64]struct{}{id: {}}
+
+	stack := append([]int64(nil), t.children[id]...)
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if _, ok := visited[n]; ok {
+			continue
+		}
+
+		visited[n] = struct{}{}
+		out = append(out, n)
+		stack = append(stack, t.children[n]...)
+	}
+
+	idutil.Sort(out)
+
+	return out
+}
+
+// Ancestors возвращает цепочку регионов-родителей id от ближайшего к корню.
+// При цикле в справочнике цепочка обрывается на первом повторном регионе.
+func (t *Tree) Ancestors(id int64) []int64 {
+	var out []int64
+
+	visited := map[int64]struct{}{id: {}}
+
+	for r, ok := t.regions[id]; ok && r.HasParent; r, ok = t.regions[r.ParentID] {
+		if _, seen := visited[r.ParentID]; seen {
+			break
+		}
+
+		visited[r.ParentID] = struct{}{}
+		out = append(out, r.ParentID)
+	}
+
+	return out
+}
+
+// IsAncestor сообщает, что регион id вложен в ancestor.
+func (t *Tree) IsAncestor(ancestor, id int64) bool {
+	for _, a := range t.Ancestors(id) {
+		if a == ancestor {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Expand заменяет положительные регионы списка на непосредственно вложенные в них регионы.
+// Регионы без вложенных и отрицательные регионы остаются без изменений.
+func (t *Tree) Expand(ids []int64) []int64 {
+	out := make([]int64, 0, len(ids))
+
+	for _, id := range ids {
+		children := t.children[id]
+		if id < 0 || len(children) == 0 {
+			out = append(out, id)
+
+			continue
+		}
+
+		out = append(out, children...)
+	}
+
+	return out
+}
+
+// Validate проверяет список регионов группы: регионы существуют, не повторяются,
+// а каждый исключенный регион вложен в какой-либо включенный.
+func (t *Tree) Validate(ids []int64) error {
+	seen := make(map[int64]struct{}, len(ids))
+	positive := map[int64]struct{}{}
+
+	for _, id := range ids {
+		abs := absID(id)
+		if _, ok := t.regions[abs]; !ok {
+			return fmt.Errorf("%w: %d", ErrUnknownRegion, abs)
+		}
+
+		if _, ok := seen[abs]; ok {
+			return fmt.Errorf("%w: %d", ErrDuplicateRegion, abs)
+		}
+
+		seen[abs] = struct{}{}
+
+		if id >= 0 {
+			positive[id] = struct{}{}
+		}
+	}
+
+	for _, id := range ids {
+		if id >= 0 {
+			continue
+		}
+
+		inside := false
+
+		for _, a := range t.Ancestors(-id) {
+			if _, ok := positive[a]; ok {
+				inside = true
+
+				break
+			}
+		}
+
+		if !inside {
+			return fmt.Errorf("%w: %d", ErrOrphanNegative, id)
+		}
+	}
+
+	return nil
+}
+
+// Covers сообщает, показываются ли объявления в регионе region при списке регионов ids.
+func (t *Tree) Covers(ids []int64, region int64) bool {
+	marks := marksOf(ids)
+
+	for _, id := range append([]int64{region}, t.Ancestors(region)...) {
+		if sign, marked := marks[id]; marked {
+			return sign
+		}
+	}
+
+	return false
+}
+
+// Normalize приводит список к минимальному виду с тем же охватом.
+func (t *Tree) Normalize(ids []int64) []int64 {
+	return t.compress(t.coverage(ids))
+}
+
+// Union возвращает список, охватывающий регионы обоих списков.
+func (t *Tree) Union(a, b []int64) []int64 {
+	ca, cb := t.coverage(a), t.coverage(b)
+	for id := range ca {
+		ca[id] = ca[id] || cb[id]
+	}
+
+	return t.compress(ca)
+}
+
+// Intersect возвращает список, охватывающий регионы, входящие в оба списка.
+func (t *Tree) Intersect(a, b []int64) []int64 {
+	ca, cb := t.coverage(a), t.coverage(b)
+	for id := range ca {
+		ca[id] = ca[id] && cb[id]
+	}
+
+	return t.compress(ca)
+}
+
+// Subtract возвращает список, охватывающий регионы из a, не входящие в b.
+func (t *Tree) Subtract(a, b []int64) []int64 {
+	ca, cb := t.coverage(a), t.coverage(b)
+	for id := range ca {
+		ca[id] = ca[id] && !cb[id]
+	}
+
+	return t.compress(ca)
+}
+
+// Exclude исключает регионы из списка, например Exclude([225], 213) дает [225, -213].
+func (t *Tree) Exclude(ids []int64, regions ...int64) []int64 {
+	return t.Subtract(ids, regions)
+}
+
+// Format возвращает список регионов названиями на языке lang, исключенные регионы — со знаком «-».
+func (t *Tree) Format(ids []int64, lang string) string {
+	parts := make([]string, 0, len(ids))
+
+	for _, id := range ids {
+		name := t.Name(absID(id), lang)
+		if id < 0 {
+			name = "-" + name
+		}
+
+		parts = append(parts, name)
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// coverage вычисляет для каждого региона дерева, охвачен ли он списком.
+func (t *Tree) coverage(ids []int64) map[int64]bool {
+	marks := marksOf(ids)
+	out := make(map[int64]bool, len(t.regions))
+
+	var walk func(id int64, inherited bool)
+	walk = func(id int64, inherited bool) {
+		state := inherited
+		if sign, ok := marks[id]; ok {
+			state = sign
+		}
+
+		out[id] = state
+
+		for _, child := range t.children[id] {
+			walk(child, state)
+		}
+	}
+
+	for _, root := range t.roots {
+		walk(root, false)
+	}
+
+	return out
+}
+
+// compress строит минимальный список: регион попадает в список, если его охват отличается от охвата родителя.
+func (t *Tree) compress(cov map[int64]bool) []int64 {
+	var out []int64
+
+	var walk func(id int64, inherited bool)
+	walk = func(id int64, inherited bool) {
+		state := cov[id]
+
+		switch {
+		case state && !inherited:
+			out = append(out, id)
+		case !state && inherited:
+			out = append(out, -id)
+		}
+
+		for _, child := range t.children[id] {
+			walk(child, state)
+		}
+	}
+
+	for _, root := range t.roots {
+		walk(root, false)
+	}
+
+	return out
+}
+
+// ParseIDs разбирает список регионов вида «225,-213».
+func ParseIDs(s string) ([]int64, error) {
+	var out []int64
+
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse region %q: %w", part, err)
+		}
+
+		out = append(out, id)
+	}
+
+	return out, nil
+}
+
+// FormatIDs возвращает список регионов в виде «225,-213».
+func FormatIDs(ids []int64) string {
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, strconv.FormatInt(id, 10))
+	}
+
+	return strings.Join(parts, ",")
+}
+
+func marksOf(ids []int64) map[int64]bool {
+	marks := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		marks[absID(id)] = id >= 0
+	}
+
+	return marks
+}
+
+func normalizeName(name string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "ё", "е")
+}
+
+func absID(id int64) int64 {
+	if id < 0 {
+		return -id
+	}
+
+	return id
+}
+
+func containsID(ids []int64, id int64) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,116 @@
+package geo
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mg-realcom/yandex-direct-sdk/dictionaries"
+)
+
+func region(id int64, parent *int64, name string) dictionaries.GeoRegionsItem {
+	return dictionaries.GeoRegionsItem{GeoRegionID: id, ParentID: parent, GeoRegionName: name}
+}
+
+func parent(id int64) *int64 {
+	return &id
+}
+
+// testTree: 225 Россия > 3 ЦФО > 1 Москва и область > 213 Москва; 10 и 11 ссылаются друг на друга,
+// цикл разрывается на меньшем идентификаторе: 10 > 11.
+func testTree() *Tree {
+	return NewTree("ru", []dictionaries.GeoRegionsItem{
+		region(225, nil, "Россия"),
+		region(3, parent(225), "Центральный федеральный округ"),
+		region(1, parent(3), "Москва и Московская область"),
+		region(213, parent(1), "Москва"),
+		region(10, parent(11), "Цикл А"),
+		region(11, parent(10), "Цикл Б"),
+	})
+}
+
+func TestAncestors(t *testing.T) {
+	tree := testTree()
+
+	tests := []struct {
+		id   int64
+		want []int64
+	}{
+		{id: 213, want: []int64{1, 3, 225}},
+		{id: 225, want: nil},
+		{id: 10, want: nil},
+		{id: 11, want: []int64{10}},
+		{id: 999, want: nil},
+	}
+
+	for _, tt := range tests {
+		if got := tree.Ancestors(tt.id); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Ancestors(%d) = %v; want %v", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestDescendants(t *testing.T) {
+	tree := testTree()
+
+	tests := []struct {
+		id   int64
+		want []int64
+	}{
+		{id: 225, want: []int64{1, 3, 213}},
+		{id: 213, want: nil},
+		{id: 10, want: []int64{11}},
+	}
+
+	for _, tt := range tests {
+		if got := tree.Descendants(tt.id); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Descendants(%d) = %v; want %v", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestIsAncestorAndCovers(t *testing.T) {
+	tree := testTree()
+
+	tests := []struct {
+		name string
+		got  bool
+		want bool
+	}{
+		{name: "IsAncestor(225, 213)", got: tree.IsAncestor(225, 213), want: true},
+		{name: "IsAncestor(213, 225)", got: tree.IsAncestor(213, 225), want: false},
+		{name: "IsAncestor(10, 10)", got: tree.IsAncestor(10, 10), want: false},
+		{name: "IsAncestor(12, 10)", got: tree.IsAncestor(12, 10), want: false},
+		{name: "Covers([225,-1], 213)", got: tree.Covers([]int64{225, -1}, 213), want: false},
+		{name: "Covers([225,-1], 3)", got: tree.Covers([]int64{225, -1}, 3), want: true},
+		{name: "Covers([225], 10)", got: tree.Covers([]int64{225}, 10), want: false},
+		{name: "Covers([10], 11)", got: tree.Covers([]int64{10}, 11), want: true},
+		{name: "Covers([11], 10)", got: tree.Covers([]int64{11}, 10), want: false},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v; want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tree := testTree()
+
+	tests := []struct {
+		ids  []int64
+		want []int64
+	}{
+		{ids: []int64{225, 213}, want: []int64{225}},
+		{ids: []int64{225, -1, 213}, want: []int64{225, -1, 213}},
+		{ids: []int64{1, 213}, want: []int64{1}},
+		{ids: []int64{11}, want: []int64{11}},
+		{ids: []int64{10, 11}, want: []int64{10}},
+	}
+
+	for _, tt := range tests {
+		if got := tree.Normalize(tt.ids); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Normalize(%v) = %v; want %v", tt.ids, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package yandex_direct_sdk
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mg-realcom/yandex-direct-sdk/statistics"
+)
+
+var (
+	ErrNoGoals      = errors.New("report definition has no goals")
+	ErrTooManyGoals = errors.New("report definition has more goals than statistics.MaxGoals")
+)
+
+// GetGoalReport получает отчет с показателями по целям и сохраняет его в dir одним TSV-файлом в длинном
+// формате, сжатым так же, как части отчетов (см. statistics.GoalUnpivot). Если целей больше statistics.MaxGoals, запрашивается несколько
+// отчетов, результаты которых объединяются. Промежуточные файлы удаляются.
+func (c *Client) GetGoalReport(ctx context.Context, dir string, def statistics.ReportDefinition) (string, error) {
+	if def.Goals == nil || len(*def.Goals) == 0 {
+		return "", ErrNoGoals
+	}
+
+	out, err := os.CreateTemp(dir, fmt.Sprintf("%s_goals_*.tsv%s", def.ReportName, c.compression.Ext()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create file: %w", err)
+	}
+
+	zw, err := compressWriter(out, c.compression)
+	if err != nil {
+		out.Close()
+		_ = os.Remove(out.Name())
+
+		return "", err
+	}
+
+	w := &goalWriter{w: bufio.NewWriter(zw)}
+
+	for _, part := range statistics.SplitGoals(def) {
+		if err = c.writeGoalPart(ctx, dir, part, w); err != nil {
+			break
+		}
+	}
+
+	if err == nil {
+		err = w.w.Flush()
+	}
+
+	if err == nil {
+		err = zw.Close()
+	}
+
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		_ = os.Remove(out.Name())
+
+		return "", err
+	}
+
+	return out.Name(), nil
+}
+
+func (c *Client) writeGoalPart(ctx context.Context, dir string, def statistics.ReportDefinition, w *goalWriter) error {
+	files, err := c.GetFiles(ctx, dir, def)
+	if err != nil {
+		// Загруженные части остаются в каталоге, повторный вызов продолжит загрузку по манифесту.
+		return fmt.Errorf("GetFiles %s: %w", def.ReportName, err)
+	}
+
+	defer func() {
+		for _, f := range files {
+			_ = os.Remove(f)
+		}
+	}()
+
+	for _, path := range files {
+		if err := w.copyFile(path); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// goalWriter записывает строки частей отчета в длинном формате и проверяет, что заголовки частей совпадают.
+type goalWriter struct {
+	w      *bufio.Writer
+	header []string
+}
+
+func (g *goalWriter) copyFile(path string) error {
+	r, err := OpenReport(path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	u, err := statistics.NewGoalUnpivot(r.Header())
+	if err != nil {
+		return err
+	}
+
+	if err := g.writeHeader(u.Header()); err != nil {
+		return err
+	}
+
+	for {
+		row, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		for _, line := range u.Rows(row) {
+			if _, err := g.w.WriteString(strings.Join(line, "\t") + "\n"); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (g *goalWriter) writeHeader(header []string) error {
+	if g.header == nil {
+		g.header = header
+		_, err := g.w.WriteString(strings.Join(header, "\t") + "\n")
+
+		return err
+	}
+
+	if strings.Join(g.header, "\t") != strings.Join(header, "\t") {
+		return fmt.Errorf("columns differ between report parts: %v and %v", g.header, header)
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package yandex_direct_sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/mg-realcom/yandex-direct-sdk/statistics"
+)
+
+func goalDefinition(goals int) statistics.ReportDefinition {
+	def := testReportDefinition(100)
+	def.FieldNames = []string{"Date", "Cost", "Conversions"}
+
+	ids := make([]string, goals)
+	for i := range ids {
+		ids[i] = fmt.Sprint(i + 1)
+	}
+
+	def.Goals = &ids
+
+	return def
+}
+
+func TestGetGoalReport(t *testing.T) {
+	stub := &stubTransport{responses: []stubResponse{
+		{status: http.StatusOK, body: "Date\tCost\tConversions_1_LSC\tConversions_2_LSC\n2024-01-01\t100\t1\t2\n"},
+		{status: http.StatusOK, body: "Date\tCost\tConversions_11_LSC\n2024-01-01\t100\t5\n"},
+	}}
+	c, _ := newLoggedClient(stub)
+	dir := t.TempDir()
+
+	path, err := c.GetGoalReport(context.Background(), dir, goalDefinition(12))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stub.requests) != 2 {
+		t.Errorf("requests = %d; want 2 reports of at most %d goals", len(stub.requests), statistics.MaxGoals)
+	}
+
+	var rows [][]string
+
+	err = ReadReports([]string{path}, func(header, row []string) error {
+		if want := []string{"Date", "Cost", statistics.ColumnGoalID, statistics.ColumnAttributionModel, "Conversions"}; !reflect.DeepEqual(header, want) {
+			t.Errorf("header = %v; want %v", header, want)
+		}
+
+		rows = append(rows, row)
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{
+		{"2024-01-01", "100", "1", "LSC", "1"},
+		{"2024-01-01", "--", "2", "LSC", "2"},
+		{"2024-01-01", "100", "11", "LSC", "5"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %v; want %v", rows, want)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 {
+		t.Errorf("dir has %d entries; want only the goal report", len(entries))
+	}
+}
+
+func TestGoalLimits(t *testing.T) {
+	stub := &stubTransport{responses: []stubResponse{{status: http.StatusOK}}}
+	c, _ := newLoggedClient(stub)
+
+	if _, err := c.GetGoalReport(context.Background(), t.TempDir(), goalDefinition(0)); !errors.Is(err, ErrNoGoals) {
+		t.Errorf("GetGoalReport() error = %v; want %v", err, ErrNoGoals)
+	}
+
+	if _, err := c.GetFiles(context.Background(), t.TempDir(), goalDefinition(statistics.MaxGoals+1)); !errors.Is(err, ErrTooManyGoals) {
+		t.Errorf("GetFiles() error = %v; want %v", err, ErrTooManyGoals)
+	}
+
+	if len(stub.requests) != 0 {
+		t.Errorf("requests = %d; want 0", len(stub.requests))
+	}
+}
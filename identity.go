@@ -0,0 +1,159 @@
+package yandex_direct_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mg-realcom/yandex-direct-sdk/clients"
+)
+
+const loginInfoURL = "https://login.yandex.ru/info?format=json"
+
+var ErrLoginMismatch = errors.New("token does not grant access to login")
+
+type Role string
+
+const (
+	RoleClient         Role = "CLIENT"         // Токен принадлежит самому рекламодателю.
+	RoleRepresentative Role = "REPRESENTATIVE" // Токен принадлежит представителю рекламодателя.
+	RoleAgency         Role = "AGENCY"         // Токен принадлежит агентству или его представителю.
+)
+
+// Identity сведения о владельце токена.
+type Identity struct {
+	Login        string           // Логин владельца токена на Яндексе.
+	UID          string           // Идентификатор пользователя на Яндексе.
+	AppID        string           // Идентификатор приложения, которому выдан токен.
+	Role         Role             // Роль владельца токена в Директе.
+	AccountLogin string           // Логин аккаунта Директа: рекламодателя или агентства.
+	ClientID     int64            // Идентификатор аккаунта Директа.
+	Currency     clients.Currency // Валюта аккаунта.
+	Scopes       []string         // Права токена, если они известны источнику токена.
+}
+
+type loginInfo struct {
+	ID       string `json:"id"`
+	Login    string `json:"login"`
+	ClientID string `json:"client_id"`
+}
+
+// Identity определяет владельца токена: логин на Яндексе, роль в Директе, аккаунт и его валюту.
+func (c *Client) Identity(ctx context.Context) (Identity, error) {
+	info, err := c.loginInfo(ctx)
+	if err != nil {
+		return Identity{}, fmt.Errorf("login info: %w", err)
+	}
+
+	account, err := c.getClient(ctx, "", clients.FieldClientID, clients.FieldLogin, clients.FieldCurrency, clients.FieldType)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	identity := Identity{
+		Login:        info.Login,
+		UID:          info.ID,
+		AppID:        info.ClientID,
+		AccountLogin: account.Login,
+		ClientID:     account.ClientID,
+		Currency:     account.Currency,
+		Role:         RoleClient,
+	}
+
+	if c.Login == "" || normalizeLogin(c.Login) == normalizeLogin(account.Login) {
+		c.profile.setCurrency(account.Currency)
+	}
+
+	switch {
+	case account.Type == clients.ClientTypeAgency:
+		identity.Role = RoleAgency
+	case normalizeLogin(info.Login) != normalizeLogin(account.Login):
+		identity.Role = RoleRepresentative
+	}
+
+	if c.tokenSource != nil {
+		if token, err := c.tokenSource.Token(ctx); err == nil && token.Scope != "" {
+			identity.Scopes = strings.Fields(token.Scope)
+		}
+	}
+
+	return identity, nil
+}
+
+// ResolveLogin заполняет пустой Client.Login логином аккаунта владельца токена или проверяет,
+// что токен дает доступ к уже заданному логину. Для агентства проверяется доступ к клиенту агентства.
+func (c *Client) ResolveLogin(ctx context.Context) (Identity, error) {
+	identity, err := c.Identity(ctx)
+	if err != nil {
+		return identity, err
+	}
+
+	if c.Login == "" {
+		c.Login = identity.AccountLogin
+
+		return identity, nil
+	}
+
+	login := normalizeLogin(c.Login)
+	if login == normalizeLogin(identity.AccountLogin) || login == normalizeLogin(identity.Login) {
+		return identity, nil
+	}
+
+	if identity.Role != RoleAgency {
+		return identity, fmt.Errorf("%w: %s (token owner %s, account %s)", ErrLoginMismatch, c.Login, identity.Login, identity.AccountLogin)
+	}
+
+	if _, err := c.getClient(ctx, c.Login, clients.FieldLogin); err != nil {
+		return identity, fmt.Errorf("%w: %s: %v", ErrLoginMismatch, c.Login, err) //nolint:errorlint
+	}
+
+	return identity, nil
+}
+
+func (c *Client) loginInfo(ctx context.Context) (loginInfo, error) {
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return loginInfo{}, err
+	}
+
+	ctx = WithCallInfo(ctx, CallInfo{Service: "login", Method: "info", Login: c.Login, Attempt: 1, Started: time.Now()})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, loginInfoURL, nil)
+	if err != nil {
+		return loginInfo{}, fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "OAuth "+token)
+
+	resp, err := c.doAuthorized(req)
+	if err != nil {
+		return loginInfo{}, fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return loginInfo{}, fmt.Errorf("StatusCode: %v", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return loginInfo{}, fmt.Errorf("read body: %w", err)
+	}
+
+	var info loginInfo
+	if err := json.Unmarshal(body, &info); err != nil {
+		return loginInfo{}, fmt.Errorf("unmarshal body: %w", err)
+	}
+
+	return info, nil
+}
+
+// normalizeLogin приводит логин к виду, в котором Яндекс сравнивает логины: без регистра, «.» равна «-».
+func normalizeLogin(login string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(login)), ".", "-")
+}
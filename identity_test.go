@@ -0,0 +1,218 @@
+package yandex_direct_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mg-realcom/yandex-direct-sdk/clients"
+)
+
+// identityServer отвечает на login.yandex.ru/info и clients.get. Токен "expired" получает 401 на любом адресе.
+type identityServer struct {
+	owner    string
+	accounts map[string]clients.ClientGetItem // Ключ — заголовок Client-Login, пустой — аккаунт владельца токена.
+
+	mu       sync.Mutex
+	auth     []string
+	logins   []string
+	clientsN int
+}
+
+func (s *identityServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/token" {
+		fmt.Fprint(w, `{"access_token":"fresh","expires_in":3600}`)
+
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	auth := r.Header.Get("Authorization")
+	s.auth = append(s.auth, r.URL.Path+" "+auth)
+
+	if strings.HasSuffix(auth, " expired") {
+		w.WriteHeader(http.StatusUnauthorized)
+
+		return
+	}
+
+	switch r.URL.Path {
+	case "/info":
+		fmt.Fprintf(w, `{"id":"42","login":%q,"client_id":"app"}`, s.owner)
+	case "/json/v5/clients":
+		s.clientsN++
+
+		login, ok := r.Header["Client-Login"]
+		if ok {
+			s.logins = append(s.logins, login[0])
+		} else {
+			s.logins = append(s.logins, "")
+		}
+
+		var items []clients.ClientGetItem
+		if item, ok := s.accounts[strings.Join(login, "")]; ok {
+			items = append(items, item)
+		}
+
+		result, _ := json.Marshal(clients.GetResult{Clients: items})
+		fmt.Fprintf(w, `{"result":%s}`, result)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newIdentityClient(t *testing.T, srv *identityServer, login, token string) *Client {
+	t.Helper()
+
+	c := newOAuthClient(t, srv)
+	c.Login = login
+	c.SetTokenSource(c.NewRefreshingTokenSource(Token{AccessToken: token, RefreshToken: "refresh"}))
+
+	return c
+}
+
+func TestIdentity(t *testing.T) {
+	tests := []struct {
+		name    string
+		owner   string
+		account clients.ClientGetItem
+		role    Role
+	}{
+		{
+			name:    "client",
+			owner:   "ivan.petrov",
+			account: clients.ClientGetItem{ClientID: 1, Login: "Ivan-Petrov", Currency: "RUB", Type: clients.ClientTypeClient},
+			role:    RoleClient,
+		},
+		{
+			name:    "representative",
+			owner:   "manager",
+			account: clients.ClientGetItem{ClientID: 2, Login: "shop", Currency: "KZT", Type: clients.ClientTypeClient},
+			role:    RoleRepresentative,
+		},
+		{
+			name:    "agency",
+			owner:   "agency-manager",
+			account: clients.ClientGetItem{ClientID: 3, Login: "agency", Currency: "RUB", Type: clients.ClientTypeAgency},
+			role:    RoleAgency,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &identityServer{owner: tt.owner, accounts: map[string]clients.ClientGetItem{"": tt.account}}
+			c := newIdentityClient(t, srv, "", "valid")
+
+			identity, err := c.Identity(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if identity.Role != tt.role || identity.Login != tt.owner || identity.AccountLogin != tt.account.Login ||
+				identity.ClientID != tt.account.ClientID || identity.Currency != tt.account.Currency {
+				t.Errorf("identity = %+v", identity)
+			}
+
+			currency, err := c.Currency(context.Background())
+			if err != nil || currency != tt.account.Currency {
+				t.Errorf("Currency() = %q, %v; want %q", currency, err, tt.account.Currency)
+			}
+
+			if srv.clientsN != 1 {
+				t.Errorf("clients.get calls = %d; want 1, currency must be seeded by Identity", srv.clientsN)
+			}
+		})
+	}
+}
+
+func TestIdentityRefreshesExpiredToken(t *testing.T) {
+	srv := &identityServer{
+		owner:    "owner",
+		accounts: map[string]clients.ClientGetItem{"": {Login: "owner", Type: clients.ClientTypeClient}},
+	}
+	c := newIdentityClient(t, srv, "", "expired")
+
+	if _, err := c.Identity(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"/info OAuth expired",
+		"/info OAuth fresh",
+		"/json/v5/clients Bearer fresh",
+	}
+	if strings.Join(srv.auth, "\n") != strings.Join(want, "\n") {
+		t.Errorf("requests = %q; want %q", srv.auth, want)
+	}
+
+	if len(srv.logins) != 1 || srv.logins[0] != "" {
+		t.Errorf("Client-Login = %q; want no header", srv.logins)
+	}
+}
+
+func TestResolveLogin(t *testing.T) {
+	owner := clients.ClientGetItem{Login: "shop-one", Currency: "RUB", Type: clients.ClientTypeClient}
+	agency := clients.ClientGetItem{Login: "agency", Currency: "RUB", Type: clients.ClientTypeAgency}
+	agencyClient := clients.ClientGetItem{Login: "agency-client", Currency: "RUB", Type: clients.ClientTypeClient}
+
+	tests := []struct {
+		name     string
+		owner    string
+		accounts map[string]clients.ClientGetItem
+		login    string
+		want     string
+		err      error
+	}{
+		{name: "empty login", owner: "shop-one", accounts: map[string]clients.ClientGetItem{"": owner}, want: "shop-one"},
+		{name: "dot equals dash", owner: "shop-one", accounts: map[string]clients.ClientGetItem{"": owner}, login: "Shop.One", want: "Shop.One"},
+		{name: "representative", owner: "manager", accounts: map[string]clients.ClientGetItem{"": owner}, login: "manager", want: "manager"},
+		{name: "foreign login", owner: "shop-one", accounts: map[string]clients.ClientGetItem{"": owner}, login: "other", err: ErrLoginMismatch},
+		{
+			name:     "agency client",
+			owner:    "agency",
+			accounts: map[string]clients.ClientGetItem{"": agency, "agency-client": agencyClient},
+			login:    "agency-client",
+			want:     "agency-client",
+		},
+		{name: "not agency client", owner: "agency", accounts: map[string]clients.ClientGetItem{"": agency}, login: "other", err: ErrLoginMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newIdentityClient(t, &identityServer{owner: tt.owner, accounts: tt.accounts}, tt.login, "valid")
+
+			_, err := c.ResolveLogin(context.Background())
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("ResolveLogin() error = %v; want %v", err, tt.err)
+			}
+
+			if tt.err == nil && c.Login != tt.want {
+				t.Errorf("Login = %q; want %q", c.Login, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeLogin(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		equal bool
+	}{
+		{a: "ivan.petrov", b: "ivan-petrov", equal: true},
+		{a: " Ivan.Petrov ", b: "ivan-petrov", equal: true},
+		{a: "ivan.petrov", b: "ivanpetrov", equal: false},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeLogin(tt.a) == normalizeLogin(tt.b); got != tt.equal {
+			t.Errorf("normalizeLogin(%q) == normalizeLogin(%q) = %v; want %v", tt.a, tt.b, got, tt.equal)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package incremental
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	sdk "github.com/mg-realcom/yandex-direct-sdk"
+	"github.com/mg-realcom/yandex-direct-sdk/common"
+	"github.com/mg-realcom/yandex-direct-sdk/statistics"
+)
+
+const (
+	dateLayout = "2006-01-02"
+	dateField  = "Date"
+	pageLimit  = 50_000
+)
+
+var (
+	ErrNoDateField = errors.New("report definition must include Date field")
+	ErrEmptyPeriod = errors.New("report period is empty")
+)
+
+// Partition часть данных назначения: статистика одного логина за одну дату.
+type Partition struct {
+	Login      string
+	Date       string
+	ReportType statistics.ReportType
+}
+
+// Sink назначение загрузки. ReplacePartition заменяет все строки партиции переданными; пустой rows очищает партицию.
+type Sink interface {
+	ReplacePartition(ctx context.Context, p Partition, header []string, rows [][]string) error
+}
+
+// Loader загружает статистику инкрементально: запрашивает отчет за период AUTO и перезаписывает
+// в назначении только даты этого периода. Границы периода AUTO определяет Директ: он включает
+// сегодняшний и вчерашний день, а также прошедшие даты, статистику за которые скорректировал.
+type Loader struct {
+	client *sdk.Client
+	sink   Sink
+	state  *State
+	dir    string
+	now    func() time.Time
+}
+
+// Result результат загрузки логина.
+type Result struct {
+	Window    []string // Даты периода AUTO, который вернул Директ. Перезаписываются все, включая даты без строк.
+	Corrected []string // Даты периода AUTO раньше вчерашнего, уже загруженные ранее: Директ включил их в AUTO из-за корректировки статистики.
+	Gap       []string // Даты между прошлой загрузкой и периодом AUTO, загруженные отдельным отчетом.
+	Rows      int
+}
+
+// NewLoader создает загрузчик. Файлы отчетов временно сохраняются в dir и удаляются после загрузки.
+func NewLoader(client *sdk.Client, sink Sink, state *State, dir string) *Loader {
+	return &Loader{client: client, sink: sink, state: state, dir: dir, now: time.Now}
+}
+
+// Load загружает статистику логина клиента по определению отчета def. DateRangeType и период в def
+// заменяются на AUTO, границы периода берутся из строки с названием отчета. Если после прошлой
+// загрузки прошло больше дней, чем охватывает AUTO, пропущенные даты загружаются отчетом за произвольный период.
+func (l *Loader) Load(ctx context.Context, def statistics.ReportDefinition) (Result, error) {
+	login := l.client.Login
+
+	if !hasField(def.FieldNames, dateField) {
+		return Result{}, ErrNoDateField
+	}
+
+	prev, _ := l.state.Get(login)
+
+	// Даты статистики считаются по московскому времени, как в Директе, а не в часовом поясе процесса.
+	now := l.now().In(statistics.Moscow)
+	today := now.Format(dateLayout)
+
+	auto := def
+	auto.DateRangeType = statistics.DateRangeAuto
+
+	if auto.Selection != nil {
+		selection := *auto.Selection
+		selection.DateFrom, selection.DateTo = "", ""
+		auto.Selection = &selection
+	}
+
+	// Период AUTO зависит от дня запроса, поэтому дата входит в имя: иначе Директ вернул бы
+	// отчет, сформированный под тем же именем в другой день.
+	auto = l.prepare(auto, "auto_"+today)
+
+	files, period, err := l.client.GetFilesWithPeriod(ctx, l.dir, auto)
+	if errors.Is(err, sdk.ErrNoReportPeriod) {
+		removeFiles(files)
+	}
+
+	if err != nil {
+		// Загруженные части остаются в каталоге, повторный запуск продолжит загрузку по манифесту.
+		return Result{}, fmt.Errorf("AUTO report: %w", err)
+	}
+
+	byDate, header, err := readByDate(files)
+	if err != nil {
+		return Result{}, fmt.Errorf("AUTO report: %w", err)
+	}
+
+	window := datesBetween(period.From, period.To)
+	if len(window) == 0 {
+		return Result{}, fmt.Errorf("AUTO report: %w: %s - %s", ErrEmptyPeriod, period.From, period.To)
+	}
+
+	res := Result{Window: window}
+
+	if gapFrom, gapTo, ok := gap(prev.LastDate, window[0]); ok {
+		custom := def
+		custom.DateRangeType = statistics.DateRangeCustomDate
+		selection := statistics.SelectionCriteria{}
+
+		if def.Selection != nil {
+			selection = *def.Selection
+		}
+
+		selection.DateFrom, selection.DateTo = gapFrom, gapTo
+		custom.Selection = &selection
+		custom = l.prepare(custom, "gap")
+
+		gapRows, gapHeader, err := l.fetch(ctx, custom)
+		if err != nil {
+			return Result{}, fmt.Errorf("gap report %s..%s: %w", gapFrom, gapTo, err)
+		}
+
+		if header == nil {
+			header = gapHeader
+		}
+
+		res.Gap = datesBetween(gapFrom, gapTo)
+		for date, rows := range gapRows {
+			byDate[date] = rows
+		}
+	}
+
+	yesterday := now.AddDate(0, 0, -1).Format(dateLayout)
+
+	for _, date := range window {
+		if date < yesterday && prev.LastDate != "" && date <= prev.LastDate {
+			res.Corrected = append(res.Corrected, date)
+		}
+	}
+
+	for _, date := range append(append([]string(nil), res.Gap...), window...) {
+		rows := byDate[date]
+		if err := l.sink.ReplacePartition(ctx, Partition{Login: login, Date: date, ReportType: def.ReportType}, header, rows); err != nil {
+			return res, fmt.Errorf("replace partition %s: %w", date, err)
+		}
+
+		res.Rows += len(rows)
+	}
+
+	last := window[len(window)-1]
+	if prev.LastDate > last {
+		last = prev.LastDate
+	}
+
+	err = l.state.Set(login, LoginState{LastDate: last, Window: window, Corrected: res.Corrected, LoadedAt: l.now()})
+
+	return res, err
+}
+
+// fetch получает отчет и группирует строки по дате.
+func (l *Loader) fetch(ctx context.Context, def statistics.ReportDefinition) (map[string][][]string, []string, error) {
+	files, err := l.client.GetFiles(ctx, l.dir, def)
+	if err != nil {
+		// Загруженные части остаются в каталоге, повторный запуск продолжит загрузку по манифесту.
+		return nil, nil, err
+	}
+
+	return readByDate(files)
+}
+
+// readByDate читает части отчета, группирует строки по дате и удаляет файлы.
+func readByDate(files []string) (map[string][][]string, []string, error) {
+	defer removeFiles(files)
+
+	byDate := map[string][][]string{}
+
+	var header []string
+
+	err := sdk.ReadReports(files, func(h, row []string) error {
+		header = h
+
+		idx := sdk.ColumnIndex(h, dateField)
+		if idx < 0 || idx >= len(row) {
+			return ErrNoDateField
+		}
+
+		byDate[row[idx]] = append(byDate[row[idx]], row)
+
+		return nil
+	})
+
+	return byDate, header, err
+}
+
+func removeFiles(files []string) {
+	for _, f := range files {
+		_ = os.Remove(f)
+	}
+}
+
+// prepare задает постраничную выборку, формат и имя отчета. Имя строится Client.ReportName
+// по окончательному определению, поэтому prepare вызывается последним.
+func (l *Loader) prepare(def statistics.ReportDefinition, suffix string) statistics.ReportDefinition {
+	out := def
+	out.Page = &common.Page{Limit: pageLimit}
+
+	if def.Page != nil && def.Page.Limit > 0 {
+		out.Page.Limit = def.Page.Limit
+	}
+
+	if out.Format == "" {
+		out.Format = common.FormatTSV
+	}
+
+	prefix := suffix
+	if def.ReportName != "" {
+		prefix = def.ReportName + "_" + suffix
+	}
+
+	out.ReportName = l.client.ReportName(prefix, out)
+
+	return out
+}
+
+// gap возвращает пропущенный период между последней загруженной датой и началом периода AUTO.
+func gap(lastDate, windowStart string) (string, string, bool) {
+	if lastDate == "" {
+		return "", "", false
+	}
+
+	last, err := time.Parse(dateLayout, lastDate)
+	if err != nil {
+		return "", "", false
+	}
+
+	start, err := time.Parse(dateLayout, windowStart)
+	if err != nil {
+		return "", "", false
+	}
+
+	from, to := last.AddDate(0, 0, 1), start.AddDate(0, 0, -1)
+	if from.After(to) {
+		return "", "", false
+	}
+
+	return from.Format(dateLayout), to.Format(dateLayout), true
+}
+
+// datesBetween возвращает даты периода включительно. Для неразборчивых дат или from позже to период пуст.
+func datesBetween(from, to string) []string {
+	start, err := time.Parse(dateLayout, from)
+	if err != nil {
+		return nil
+	}
+
+	end, err := time.Parse(dateLayout, to)
+	if err != nil {
+		return nil
+	}
+
+	var out []string
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		out = append(out, d.Format(dateLayout))
+	}
+
+	return out
+}
+
+func hasField(fields []string, name string) bool {
+	for _, f := range fields {
+		if f == name {
+			return true
+		}
+	}
+
+	return false
+}
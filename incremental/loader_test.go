@@ -0,0 +1,203 @@
+package incremental
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/mg-realcom/yandex-direct-sdk"
+	"github.com/mg-realcom/yandex-direct-sdk/statistics"
+	"github.com/rs/zerolog"
+)
+
+// stubTransport возвращает отчеты по порядку и запоминает запросы.
+type stubTransport struct {
+	bodies   []string
+	requests []*http.Request
+}
+
+func (s *stubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	s.requests = append(s.requests, req)
+
+	body := s.bodies[0]
+	s.bodies = s.bodies[1:]
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// memorySink запоминает замененные партиции.
+type memorySink struct {
+	dates []string
+	rows  map[string]int
+}
+
+func (m *memorySink) ReplacePartition(_ context.Context, p Partition, _ []string, rows [][]string) error {
+	m.dates = append(m.dates, p.Date)
+	m.rows[p.Date] = len(rows)
+
+	return nil
+}
+
+// testNow 01:00 по Москве: в UTC это еще предыдущий день, поэтому даты, посчитанные не по московскому времени, не совпадут.
+var testNow = time.Date(2024, 3, 10, 1, 0, 0, 0, statistics.Moscow)
+
+func day(offset int) string {
+	return testNow.AddDate(0, 0, offset).Format(dateLayout)
+}
+
+func newTestLoader(t *testing.T, bodies []string) (*Loader, *stubTransport, *memorySink) {
+	t.Helper()
+
+	dir := t.TempDir()
+	stub := &stubTransport{bodies: bodies}
+	logger := zerolog.Nop()
+	token := "token"
+	client := sdk.NewClient(&http.Client{Transport: stub}, "client", &token, &sdk.App{}, false, &logger)
+
+	state, err := LoadState(filepath.Join(dir, "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sink := &memorySink{rows: map[string]int{}}
+	l := NewLoader(client, sink, state, dir)
+	l.now = func() time.Time { return testNow.In(time.UTC) }
+
+	return l, stub, sink
+}
+
+func testDefinition() statistics.ReportDefinition {
+	return statistics.ReportDefinition{
+		FieldNames: []string{"Date", "Clicks"},
+		ReportName: "daily",
+		ReportType: statistics.CampaignPerformanceReport,
+	}
+}
+
+func TestLoadUsesAutoPeriod(t *testing.T) {
+	title := fmt.Sprintf("report (%s - %s)\n", day(-3), day(0))
+
+	tests := []struct {
+		name      string
+		lastDate  string
+		bodies    []string
+		window    []string
+		corrected []string
+		gap       []string
+		rows      map[string]int
+	}{
+		{
+			name:      "corrected dates",
+			lastDate:  day(-1),
+			bodies:    []string{title + "Date\tClicks\n" + day(-2) + "\t5\n"},
+			window:    []string{day(-3), day(-2), day(-1), day(0)},
+			corrected: []string{day(-3), day(-2)},
+			rows:      map[string]int{day(-3): 0, day(-2): 1, day(-1): 0, day(0): 0},
+		},
+		{
+			name:     "first load",
+			bodies:   []string{title + "Date\tClicks\n"},
+			window:   []string{day(-3), day(-2), day(-1), day(0)},
+			rows:     map[string]int{day(-3): 0, day(-2): 0, day(-1): 0, day(0): 0},
+			lastDate: "",
+		},
+		{
+			name:     "gap",
+			lastDate: day(-6),
+			bodies: []string{
+				title + "Date\tClicks\n" + day(0) + "\t1\n",
+				"Date\tClicks\n" + day(-5) + "\t2\n" + day(-5) + "\t3\n",
+			},
+			window: []string{day(-3), day(-2), day(-1), day(0)},
+			gap:    []string{day(-5), day(-4)},
+			rows:   map[string]int{day(-5): 2, day(-4): 0, day(-3): 0, day(-2): 0, day(-1): 0, day(0): 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, stub, sink := newTestLoader(t, tt.bodies)
+			state := l.state
+
+			if tt.lastDate != "" {
+				if err := state.Set("client", LoginState{LastDate: tt.lastDate}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			res, err := l.Load(context.Background(), testDefinition())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(res.Window, tt.window) {
+				t.Errorf("Window = %v; want %v", res.Window, tt.window)
+			}
+
+			if !reflect.DeepEqual(res.Corrected, tt.corrected) {
+				t.Errorf("Corrected = %v; want %v", res.Corrected, tt.corrected)
+			}
+
+			if !reflect.DeepEqual(res.Gap, tt.gap) {
+				t.Errorf("Gap = %v; want %v", res.Gap, tt.gap)
+			}
+
+			if !reflect.DeepEqual(sink.rows, tt.rows) {
+				t.Errorf("partitions = %v; want %v", sink.rows, tt.rows)
+			}
+
+			auto := stub.requests[0]
+			if got := auto.Header.Get("skipReportHeader"); got != "false" {
+				t.Errorf("AUTO request skipReportHeader = %q; want false", got)
+			}
+
+			body, err := auto.GetBody()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var req sdk.Request
+			if err := json.NewDecoder(body).Decode(&req); err != nil {
+				t.Fatal(err)
+			}
+
+			if prefix := "client_daily_auto_" + day(0) + "_"; !strings.HasPrefix(req.Params.ReportName, prefix) {
+				t.Errorf("AUTO ReportName = %q; want prefix %q", req.Params.ReportName, prefix)
+			}
+
+			if ls, _ := state.Get("client"); ls.LastDate != day(0) {
+				t.Errorf("saved LastDate = %q; want %q", ls.LastDate, day(0))
+			}
+		})
+	}
+}
+
+func TestLoadRejectsEmptyPeriod(t *testing.T) {
+	title := fmt.Sprintf("report (%s - %s)\n", day(0), day(-3))
+	l, _, sink := newTestLoader(t, []string{title + "Date\tClicks\n"})
+
+	if _, err := l.Load(context.Background(), testDefinition()); !errors.Is(err, ErrEmptyPeriod) {
+		t.Fatalf("Load() error = %v; want %v", err, ErrEmptyPeriod)
+	}
+
+	if len(sink.dates) != 0 {
+		t.Errorf("replaced partitions %v; want none", sink.dates)
+	}
+
+	if _, ok := l.state.Get("client"); ok {
+		t.Error("state is saved for an empty period")
+	}
+}
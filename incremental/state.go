@@ -0,0 +1,78 @@
+package incremental
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mg-realcom/yandex-direct-sdk/internal/fsutil"
+)
+
+const filePerm = 0o644
+
+// LoginState состояние загрузки логина.
+type LoginState struct {
+	LastDate  string    `json:"last_date"`           // Последняя загруженная дата, YYYY-MM-DD.
+	Window    []string  `json:"window,omitempty"`    // Даты, перезаписанные при последней загрузке.
+	Corrected []string  `json:"corrected,omitempty"` // Прошедшие даты, статистику за которые Директ скорректировал при последней загрузке.
+	LoadedAt  time.Time `json:"loaded_at"`           // Время последней загрузки.
+}
+
+// State состояние загрузки по логинам, хранящееся в JSON-файле.
+type State struct {
+	path   string
+	mu     sync.Mutex
+	logins map[string]LoginState
+}
+
+// LoadState читает состояние из файла. Отсутствующий файл означает пустое состояние.
+func LoadState(path string) (*State, error) {
+	s := &State{path: path, logins: map[string]LoginState{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("read state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &s.logins); err != nil {
+		return nil, fmt.Errorf("unmarshal state: %w", err)
+	}
+
+	return s, nil
+}
+
+// Get возвращает состояние логина.
+func (s *State) Get(login string) (LoginState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ls, ok := s.logins[login]
+
+	return ls, ok
+}
+
+// Set задает состояние логина и сохраняет файл.
+func (s *State) Set(login string, ls LoginState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.logins[login] = ls
+
+	data, err := json.MarshalIndent(s.logins, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal state: %w", err)
+	}
+
+	if err := fsutil.WriteFile(s.path, ".incremental_*", data, filePerm); err != nil {
+		return fmt.Errorf("save state: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,9 @@
+// Package idutil содержит операции над списками идентификаторов объектов Директа.
+package idutil
+
+import "sort"
+
+// Sort сортирует идентификаторы по возрастанию.
+func Sort(ids []int64) {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+}
@@ -0,0 +1,131 @@
+package yandex_direct_sdk
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HeaderUnitsUsedLogin логин, баллы которого израсходованы на запрос.
+const HeaderUnitsUsedLogin = "Units-Used-Login"
+
+// RateLimiter ограничивает частоту запросов к API.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+type intervalLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// NewRateLimiter создает ограничитель, пропускающий не более limit запросов за период per, равномерно распределяя их.
+func NewRateLimiter(limit int, per time.Duration) RateLimiter { //nolint:ireturn
+	if limit < 1 {
+		limit = 1
+	}
+
+	return &intervalLimiter{interval: per / time.Duration(limit)}
+}
+
+func (l *intervalLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next
+
+	if slot.Before(now) {
+		slot = now
+	}
+
+	l.next = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err() //nolint:wrapcheck
+	case <-timer.C:
+		return nil
+	}
+}
+
+// SetRateLimiter задает ограничитель частоты запросов к API. Nil отключает ограничение.
+func (c *Client) SetRateLimiter(l RateLimiter) {
+	c.limiter = l
+}
+
+// UnitsUsage расход баллов логина.
+type UnitsUsage struct {
+	Last     Units     // Значения из последнего ответа.
+	Spent    int64     // Израсходовано клиентом с момента создания учета.
+	Requests int64     // Количество ответов с заголовком Units.
+	Updated  time.Time // Время последнего ответа.
+}
+
+// UnitsAccounting учет баллов API по логинам. Общий для клиента и всех порожденных от него клиентов.
+type UnitsAccounting struct {
+	mu    sync.Mutex
+	usage map[string]UnitsUsage
+}
+
+func newUnitsAccounting() *UnitsAccounting {
+	return &UnitsAccounting{usage: map[string]UnitsUsage{}}
+}
+
+func (a *UnitsAccounting) observe(login string, h http.Header) {
+	units, ok := ParseUnits(h)
+	if !ok {
+		return
+	}
+
+	if used := h.Get(HeaderUnitsUsedLogin); used != "" {
+		login = used
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	usage := a.usage[login]
+	usage.Last = units
+	usage.Spent += units.Spent
+	usage.Requests++
+	usage.Updated = time.Now()
+	a.usage[login] = usage
+}
+
+// Get возвращает расход баллов логина.
+func (a *UnitsAccounting) Get(login string) (UnitsUsage, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	usage, ok := a.usage[login]
+
+	return usage, ok
+}
+
+// Snapshot возвращает копию расхода баллов по всем логинам.
+func (a *UnitsAccounting) Snapshot() map[string]UnitsUsage {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	out := make(map[string]UnitsUsage, len(a.usage))
+	for login, usage := range a.usage {
+		out[login] = usage
+	}
+
+	return out
+}
+
+// Units возвращает учет баллов клиента.
+func (c *Client) Units() *UnitsAccounting {
+	return c.units
+}
@@ -0,0 +1,80 @@
+package yandex_direct_sdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestIntervalLimiterSpacing(t *testing.T) {
+	const (
+		calls    = 5
+		interval = 20 * time.Millisecond
+	)
+
+	l := NewRateLimiter(50, time.Second)
+	start := time.Now()
+
+	for i := 0; i < calls; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Первый вызов проходит сразу, каждый следующий ждет свой интервал.
+	if elapsed := time.Since(start); elapsed < (calls-1)*interval {
+		t.Errorf("%d calls took %v; want at least %v", calls, elapsed, (calls-1)*interval)
+	}
+}
+
+func TestIntervalLimiterCancel(t *testing.T) {
+	l := NewRateLimiter(1, time.Hour)
+
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait() error = %v; want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestUnitsAccountingByUsedLogin(t *testing.T) {
+	tr := &stubTransport{responses: []stubResponse{
+		{status: http.StatusOK, header: http.Header{HeaderUnits: {"10/990/1000"}}, body: `{"result":{}}`},
+		{status: http.StatusOK, header: http.Header{HeaderUnits: {"5/95/100"}, HeaderUnitsUsedLogin: {"agency"}}, body: `{"result":{}}`},
+		{status: http.StatusOK, header: http.Header{HeaderUnits: {"7/88/100"}, HeaderUnitsUsedLogin: {"agency"}}, body: `{"result":{}}`},
+		{status: http.StatusOK, body: `{"result":{}}`},
+	}}
+
+	c, _ := newLoggedClient(tr)
+	sub := c.WithLogin("sub")
+
+	for _, call := range []*Client{c, sub, sub, sub} {
+		if err := call.Call(context.Background(), "campaigns", "get", struct{}{}, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	usage := c.Units().Snapshot()
+	if len(usage) != 2 {
+		t.Fatalf("usage = %+v; want logins client and agency", usage)
+	}
+
+	if u := usage["client"]; u.Spent != 10 || u.Requests != 1 || u.Last.Rest != 990 {
+		t.Errorf("client usage = %+v", u)
+	}
+
+	if u := usage["agency"]; u.Spent != 12 || u.Requests != 2 || u.Last.Rest != 88 {
+		t.Errorf("agency usage = %+v", u)
+	}
+
+	if _, ok := c.Units().Get("sub"); ok {
+		t.Error("sub login is accounted; units were spent by the agency")
+	}
+}
@@ -0,0 +1,141 @@
+package yandex_direct_sdk
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mg-realcom/yandex-direct-sdk/statistics"
+	"github.com/rs/zerolog"
+)
+
+const (
+	testAccessToken  = "y0_secret-access-token"
+	testRefreshToken = "1:secret-refresh-token"
+	testAppSecret    = "secret-app-password"
+)
+
+// stubTransport возвращает ответы по порядку и запоминает запросы.
+type stubTransport struct {
+	responses []stubResponse
+	requests  []*http.Request
+}
+
+type stubResponse struct {
+	status int
+	header http.Header
+	body   string
+}
+
+func (s *stubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	s.requests = append(s.requests, req)
+
+	r := s.responses[0]
+	if len(s.responses) > 1 {
+		s.responses = s.responses[1:]
+	}
+
+	header := r.header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		StatusCode: r.status,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(r.body)),
+		Request:    req,
+	}, nil
+}
+
+func newLoggedClient(tr http.RoundTripper) (*Client, *bytes.Buffer) {
+	buf := &bytes.Buffer{}
+	logger := zerolog.New(buf)
+	token := testAccessToken
+
+	return NewClient(&http.Client{Transport: tr}, "client", &token, &App{ID: "app", Secret: testAppSecret}, false, &logger), buf
+}
+
+func assertNoSecrets(t *testing.T, logs string) {
+	t.Helper()
+
+	if logs == "" {
+		t.Fatal("nothing was logged")
+	}
+
+	for _, secret := range []string{testAccessToken, testRefreshToken, testAppSecret} {
+		if strings.Contains(logs, secret) {
+			t.Errorf("secret %q reached the logs:\n%s", secret, logs)
+		}
+	}
+}
+
+func TestLogsHideSecrets(t *testing.T) {
+	tests := []struct {
+		name string
+		resp stubResponse
+		call func(t *testing.T, c *Client) error
+	}{
+		{
+			name: "refresh token error",
+			resp: stubResponse{
+				status: http.StatusBadRequest,
+				body:   `{"error":"invalid_grant","error_description":"bad refresh_token=` + testRefreshToken + `"}`,
+			},
+			call: func(_ *testing.T, c *Client) error {
+				_, err := c.RefreshToken(context.Background(), testRefreshToken)
+
+				return err
+			},
+		},
+		{
+			name: "token in oauth response that failed to parse",
+			resp: stubResponse{
+				status: http.StatusOK,
+				body:   `{"access_token":"` + testAccessToken + `","refresh_token":"` + testRefreshToken + `",`,
+			},
+			call: func(_ *testing.T, c *Client) error {
+				_, err := c.RefreshToken(context.Background(), testRefreshToken)
+
+				return err
+			},
+		},
+		{
+			name: "device code request failure",
+			resp: stubResponse{status: http.StatusInternalServerError, body: "internal"},
+			call: func(_ *testing.T, c *Client) error {
+				_, err := c.GetAccessCode()
+
+				return err
+			},
+		},
+		{
+			name: "report server error",
+			resp: stubResponse{status: http.StatusInternalServerError, body: `{"error":{"error_code":"1000"}}`},
+			call: func(t *testing.T, c *Client) error {
+				def := statistics.NewReport(statistics.AccountPerformanceReport).
+					DateRange("2024-01-01", "2024-01-01").
+					Fields(statistics.FieldDate, statistics.FieldClicks).
+					MustBuild()
+				_, err := c.GetFiles(context.Background(), t.TempDir(), def)
+
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, logs := newLoggedClient(&stubTransport{responses: []stubResponse{tt.resp}})
+
+			if err := tt.call(t, c); err == nil {
+				t.Fatal("expected an error")
+			}
+
+			assertNoSecrets(t, logs.String())
+		})
+	}
+}
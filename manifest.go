@@ -0,0 +1,264 @@
+package yandex_direct_sdk
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mg-realcom/yandex-direct-sdk/internal/fsutil"
+	"github.com/mg-realcom/yandex-direct-sdk/statistics"
+)
+
+const (
+	manifestSuffix     = ".manifest.json"
+	manifestTempPrefix = ".manifest_"
+	manifestPerm       = 0o600
+)
+
+// partFilePattern имя файла части отчета: <имя отчета>_part_<номер>_<случайный суффикс>.tsv[.gz|.zst].
+var partFilePattern = regexp.MustCompile(`_part_\d+_\d+\.tsv(\.gz|\.zst)?$`)
+
+// Manifest состояние загрузки многостраничного отчета. Хранится рядом с частями отчета, пока загрузка
+// не завершена, и позволяет повторному вызову GetFiles продолжить со следующей страницы.
+type Manifest struct {
+	Hash       string                      `json:"hash"` // Хеш определения, логина и заголовков (statistics.Hash).
+	Login      string                      `json:"login"`
+	ReportName string                      `json:"report_name"` // Имя отчета без номера части; меняется при конфликте имен.
+	Limit      int                         `json:"limit"`
+	Definition statistics.ReportDefinition `json:"definition"`
+	Parts      []ManifestPart              `json:"parts"`
+	UpdatedAt  time.Time                   `json:"updated_at"`
+}
+
+// ManifestPart загруженная часть отчета.
+type ManifestPart struct {
+	Part   int    `json:"part"`
+	Offset int    `json:"offset"`
+	File   string `json:"file"` // Имя файла в каталоге отчета.
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+func manifestPath(dir, reportName string) string {
+	return filepath.Join(dir, reportName+manifestSuffix)
+}
+
+// loadManifest читает манифест. Отсутствующий или поврежденный манифест возвращается как nil.
+func loadManifest(path string) *Manifest {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil
+	}
+
+	return &m
+}
+
+// save записывает манифест атомарно.
+func (m *Manifest) save(path string) error {
+	m.UpdatedAt = time.Now()
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal manifest: %w", err)
+	}
+
+	if err := fsutil.WriteFile(path, manifestTempPrefix+"*", data, manifestPerm); err != nil {
+		return fmt.Errorf("save manifest: %w", err)
+	}
+
+	return nil
+}
+
+// verify оставляет в манифесте только первые подряд идущие части, файлы которых существуют
+// и совпадают по размеру и контрольной сумме. Остальные файлы удаляются.
+func (m *Manifest) verify(dir string) {
+	for i, p := range m.Parts {
+		size, sum, err := checksumFile(filepath.Join(dir, p.File))
+		if err == nil && size == p.Size && sum == p.SHA256 {
+			continue
+		}
+
+		for _, bad := range m.Parts[i:] {
+			_ = os.Remove(filepath.Join(dir, bad.File))
+		}
+
+		m.Parts = m.Parts[:i]
+
+		return
+	}
+}
+
+// files возвращает пути к загруженным частям.
+func (m *Manifest) files(dir string) []string {
+	out := make([]string, 0, len(m.Parts))
+	for _, p := range m.Parts {
+		out = append(out, filepath.Join(dir, p.File))
+	}
+
+	return out
+}
+
+// resumeManifest возвращает манифест загрузки params. Если в dir есть манифест того же определения,
+// его части проверяются и загрузка продолжается; иначе создается новый манифест.
+func (c *Client) resumeManifest(path, dir string, params statistics.ReportDefinition, headers map[string]string) *Manifest {
+	hash := statistics.Hash(params, c.Login, headers)
+
+	m := loadManifest(path)
+	if m != nil && m.Hash == hash && m.Limit == params.Page.Limit {
+		m.verify(dir)
+
+		return m
+	}
+
+	return &Manifest{
+		Hash:       hash,
+		Login:      c.Login,
+		ReportName: params.ReportName,
+		Limit:      params.Page.Limit,
+		Definition: params.Canonical(),
+	}
+}
+
+func checksumFile(path string) (int64, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, "", err //nolint:wrapcheck
+	}
+	defer f.Close()
+
+	h := sha256.New()
+
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return 0, "", err //nolint:wrapcheck
+	}
+
+	return n, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Cleanup удаляет из dir файлы незавершенных загрузок: части отчетов, не попавшие в манифест,
+// временные файлы манифестов и манифесты старше maxAge вместе с их частями. Директ хранит
+// сформированные отчеты ограниченное время, поэтому старую загрузку все равно придется начинать заново.
+// Части, на которые не ссылается ни один манифест (например, оставшиеся после сбоя до сохранения
+// манифеста), удаляются, если они старше maxAge; части завершенных загрузок тоже не имеют манифеста,
+// поэтому maxAge должен быть больше времени, в течение которого вызывающий обрабатывает их.
+// Нулевой maxAge оставляет все манифесты и части без манифеста. Не вызывается одновременно с загрузкой
+// в тот же каталог. Возвращает удаленные файлы.
+func Cleanup(dir string, maxAge time.Duration) ([]string, error) {
+	items, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read dir: %w", err)
+	}
+
+	var removed []string
+
+	remove := func(name string) error {
+		err := os.Remove(filepath.Join(dir, name))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove %s: %w", name, err)
+		}
+
+		if err == nil {
+			removed = append(removed, filepath.Join(dir, name))
+		}
+
+		return nil
+	}
+
+	referenced := map[string]bool{}
+
+	for _, item := range items {
+		name := item.Name()
+
+		switch {
+		case item.IsDir():
+		case strings.HasPrefix(name, manifestTempPrefix):
+			if err := remove(name); err != nil {
+				return removed, err
+			}
+		case strings.HasSuffix(name, manifestSuffix):
+			if err := cleanupManifest(dir, name, items, maxAge, referenced, remove); err != nil {
+				return removed, err
+			}
+		}
+	}
+
+	if maxAge <= 0 {
+		return removed, nil
+	}
+
+	for _, item := range items {
+		name := item.Name()
+		if item.IsDir() || referenced[name] || !partFilePattern.MatchString(name) {
+			continue
+		}
+
+		info, err := item.Info()
+		if err != nil || time.Since(info.ModTime()) <= maxAge {
+			continue
+		}
+
+		if err := remove(name); err != nil {
+			return removed, err
+		}
+	}
+
+	return removed, nil
+}
+
+// cleanupManifest удаляет части загрузки манифеста name, которых нет в манифесте, и отмечает
+// в referenced части, которые остаются.
+func cleanupManifest(dir, name string, items []os.DirEntry, maxAge time.Duration, referenced map[string]bool, remove func(string) error) error {
+	m := loadManifest(filepath.Join(dir, name))
+	if m == nil {
+		return remove(name)
+	}
+
+	expired := maxAge > 0 && time.Since(m.UpdatedAt) > maxAge
+	keep := make(map[string]bool, len(m.Parts))
+
+	if !expired {
+		for _, p := range m.Parts {
+			keep[p.File] = true
+			referenced[p.File] = true
+		}
+	}
+
+	prefixes := []string{strings.TrimSuffix(name, manifestSuffix) + "_part_", m.ReportName + "_part_"}
+
+	for _, item := range items {
+		file := item.Name()
+		if item.IsDir() || keep[file] || strings.HasSuffix(file, manifestSuffix) {
+			continue
+		}
+
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(file, prefix) {
+				if err := remove(file); err != nil {
+					return err
+				}
+
+				break
+			}
+		}
+	}
+
+	if expired {
+		return remove(name)
+	}
+
+	return nil
+}
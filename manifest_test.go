@@ -0,0 +1,266 @@
+package yandex_direct_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func reportPage(rows int) stubResponse {
+	body := "Date\tCampaignId\tConversions_12345_LSC\n" + strings.Repeat("2024-01-01\t1\t2\n", rows)
+
+	return stubResponse{status: http.StatusOK, body: body}
+}
+
+func TestGetFilesResume(t *testing.T) {
+	const limit = 2
+
+	dir := t.TempDir()
+	def := testReportDefinition(limit)
+
+	// Первая загрузка: шесть полных страниц, на седьмой сервер отвечает ошибкой.
+	first := &stubTransport{}
+	for i := 0; i < 6; i++ {
+		first.responses = append(first.responses, reportPage(limit))
+	}
+
+	first.responses = append(first.responses, stubResponse{status: http.StatusInternalServerError})
+
+	c, _ := newLoggedClient(first)
+	if _, err := c.GetFiles(context.Background(), dir, def); err == nil {
+		t.Fatal("GetFiles() error = nil; want server error on part 7")
+	}
+
+	m := loadManifest(manifestPath(dir, def.ReportName))
+	if m == nil || len(m.Parts) != 6 {
+		t.Fatalf("manifest = %+v; want 6 parts", m)
+	}
+
+	// Повторный запуск продолжает с седьмой части; десятая часть неполная и завершает отчет.
+	rec := &bodyRecorder{next: &stubTransport{responses: []stubResponse{reportPage(limit), reportPage(limit), reportPage(limit), reportPage(1)}}}
+	c, _ = newLoggedClient(rec)
+
+	files, err := c.GetFiles(context.Background(), dir, def)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(files) != 10 {
+		t.Errorf("files = %d; want 10", len(files))
+	}
+
+	if len(rec.bodies) != 4 {
+		t.Fatalf("requests = %d; want 4", len(rec.bodies))
+	}
+
+	var req Request
+	if err := json.Unmarshal([]byte(rec.bodies[0]), &req); err != nil {
+		t.Fatal(err)
+	}
+
+	if req.Params.Page.Offset != 6*limit || req.Params.ReportName != def.ReportName+"_part_7" {
+		t.Errorf("resumed request = %s offset %d; want %s_part_7 offset %d", req.Params.ReportName, req.Params.Page.Offset, def.ReportName, 6*limit)
+	}
+
+	if _, err := os.Stat(manifestPath(dir, def.ReportName)); !os.IsNotExist(err) {
+		t.Errorf("manifest is left after the report is complete: %v", err)
+	}
+}
+
+func TestManifestVerify(t *testing.T) {
+	tests := []struct {
+		name   string
+		damage func(dir string, parts []ManifestPart) error
+		keep   int
+	}{
+		{name: "intact", damage: func(string, []ManifestPart) error { return nil }, keep: 3},
+		{
+			name: "corrupted part",
+			damage: func(dir string, parts []ManifestPart) error {
+				return os.WriteFile(filepath.Join(dir, parts[1].File), []byte("XXXXXXXX"), 0o600)
+			},
+			keep: 1,
+		},
+		{
+			name: "truncated part",
+			damage: func(dir string, parts []ManifestPart) error {
+				return os.Truncate(filepath.Join(dir, parts[2].File), 1)
+			},
+			keep: 2,
+		},
+		{
+			name: "missing part",
+			damage: func(dir string, parts []ManifestPart) error {
+				return os.Remove(filepath.Join(dir, parts[0].File))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			m := &Manifest{}
+
+			for i := 1; i <= 3; i++ {
+				name := fmt.Sprintf("r_part_%d_100.tsv", i)
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(fmt.Sprintf("part %d\n", i)), 0o600); err != nil {
+					t.Fatal(err)
+				}
+
+				size, sum, err := checksumFile(filepath.Join(dir, name))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				m.Parts = append(m.Parts, ManifestPart{Part: i, Offset: (i - 1) * 10, File: name, Size: size, SHA256: sum})
+			}
+
+			parts := append([]ManifestPart(nil), m.Parts...)
+			if err := tt.damage(dir, parts); err != nil {
+				t.Fatal(err)
+			}
+
+			m.verify(dir)
+
+			if len(m.Parts) != tt.keep {
+				t.Fatalf("parts = %d; want %d", len(m.Parts), tt.keep)
+			}
+
+			for i, p := range parts {
+				_, err := os.Stat(filepath.Join(dir, p.File))
+				if exists := err == nil; exists != (i < tt.keep) {
+					t.Errorf("part %d exists = %v; want %v", i+1, exists, i < tt.keep)
+				}
+			}
+		})
+	}
+}
+
+func TestCleanup(t *testing.T) {
+	old := time.Now().Add(-2 * time.Hour)
+
+	writeManifest := func(t *testing.T, dir, report string, updated time.Time, parts ...string) {
+		t.Helper()
+
+		m := Manifest{ReportName: report, UpdatedAt: updated}
+		for i, p := range parts {
+			m.Parts = append(m.Parts, ManifestPart{Part: i + 1, File: p})
+		}
+
+		data, err := json.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(manifestPath(dir, report), data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	setup := func(t *testing.T) string {
+		t.Helper()
+
+		dir := t.TempDir()
+
+		for _, name := range []string{
+			"fresh_part_1_111.tsv", "fresh_part_2_222.tsv", "expired_part_1_333.tsv",
+			".manifest_444", "broken.manifest.json", "orphan_part_1_555.tsv.gz", "recent_part_1_666.tsv", "notes.txt",
+		} {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		for _, name := range []string{"orphan_part_1_555.tsv.gz", "notes.txt"} {
+			if err := os.Chtimes(filepath.Join(dir, name), old, old); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		writeManifest(t, dir, "fresh", time.Now(), "fresh_part_1_111.tsv")
+		writeManifest(t, dir, "expired", old, "expired_part_1_333.tsv")
+
+		return dir
+	}
+
+	tests := []struct {
+		name    string
+		maxAge  time.Duration
+		removed []string
+	}{
+		{
+			name:   "max age",
+			maxAge: time.Hour,
+			removed: []string{
+				".manifest_444", "broken.manifest.json", "expired.manifest.json", "expired_part_1_333.tsv",
+				"fresh_part_2_222.tsv", "orphan_part_1_555.tsv.gz",
+			},
+		},
+		{
+			name:    "keep manifests",
+			removed: []string{".manifest_444", "broken.manifest.json", "fresh_part_2_222.tsv"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := setup(t)
+
+			removed, err := Cleanup(dir, tt.maxAge)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			names := make([]string, 0, len(removed))
+			for _, path := range removed {
+				names = append(names, filepath.Base(path))
+			}
+
+			sort.Strings(names)
+
+			if fmt.Sprint(names) != fmt.Sprint(tt.removed) {
+				t.Errorf("removed = %v; want %v", names, tt.removed)
+			}
+
+			for _, name := range tt.removed {
+				if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+					t.Errorf("%s is not removed", name)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateTSVFileRemovesPartialFile(t *testing.T) {
+	errBroken := errors.New("connection reset")
+
+	for _, comp := range []Compression{CompressionNone, CompressionGzip, CompressionZstd} {
+		t.Run(string(comp), func(t *testing.T) {
+			dir := t.TempDir()
+			resp := &http.Response{Body: io.NopCloser(io.MultiReader(strings.NewReader("Date\tClicks\n"), iotest.ErrReader(errBroken)))}
+
+			if _, err := createTSVFile(dir, "r_part_1", resp, comp); !errors.Is(err, errBroken) {
+				t.Fatalf("createTSVFile() error = %v; want %v", err, errBroken)
+			}
+
+			items, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(items) != 0 {
+				t.Errorf("dir has %d files; want the partial file removed", len(items))
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package metrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	sdk "github.com/mg-realcom/yandex-direct-sdk"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	namespace = "yandex_direct"
+
+	labelLogin      = "login"
+	labelService    = "service"
+	labelReportType = "report_type"
+	labelCode       = "code"
+
+	codeTransport = "transport"
+	maxErrorBody  = 64 << 10
+)
+
+// Collector набор метрик использования API Директа и конвейера отчетов.
+// Реализует prometheus.Collector и регистрируется в любом prometheus.Registerer.
+type Collector struct {
+	unitsRest      *prometheus.GaugeVec
+	unitsLimit     *prometheus.GaugeVec
+	reportDuration *prometheus.HistogramVec
+	queueDepth     *prometheus.GaugeVec
+	failures       *prometheus.CounterVec
+	tokenExpiry    *tokenExpiry
+}
+
+// New создает набор метрик.
+func New() *Collector {
+	return &Collector{
+		unitsRest: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "units_rest",
+			Help:      "Остаток баллов API по логину.",
+		}, []string{labelLogin}),
+		unitsLimit: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "units_limit",
+			Help:      "Суточный лимит баллов API по логину.",
+		}, []string{labelLogin}),
+		reportDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "report_duration_seconds",
+			Help:      "Время от первого запроса отчета до получения готового отчета.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+		}, []string{labelReportType}),
+		queueDepth: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "reports_in_queue",
+			Help:      "Количество отчетов в очереди офлайн-формирования по логину.",
+		}, []string{labelLogin}),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "failures_total",
+			Help:      "Ошибки вызовов API по сервису и коду ошибки.",
+		}, []string{labelService, labelCode}),
+		tokenExpiry: newTokenExpiry(),
+	}
+}
+
+// Register регистрирует метрики в reg.
+func (c *Collector) Register(reg prometheus.Registerer) error {
+	return reg.Register(c) //nolint:wrapcheck
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.unitsRest.Describe(ch)
+	c.unitsLimit.Describe(ch)
+	c.reportDuration.Describe(ch)
+	c.queueDepth.Describe(ch)
+	c.failures.Describe(ch)
+	c.tokenExpiry.Describe(ch)
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.unitsRest.Collect(ch)
+	c.unitsLimit.Collect(ch)
+	c.reportDuration.Collect(ch)
+	c.queueDepth.Collect(ch)
+	c.failures.Collect(ch)
+	c.tokenExpiry.Collect(ch)
+}
+
+// SetTokenExpiry задает момент истечения OAuth-токена логина для метрики обратного отсчета.
+func (c *Collector) SetTokenExpiry(login string, expiresAt time.Time) {
+	c.tokenExpiry.set(login, expiresAt)
+}
+
+// WatchTokenSource отслеживает срок действия токена логина: задает текущий срок и обновляет его после каждого обновления токена.
+func (c *Collector) WatchTokenSource(login string, ts *sdk.RefreshingTokenSource) {
+	if expiry := ts.Current().Expiry; !expiry.IsZero() {
+		c.SetTokenExpiry(login, expiry)
+	}
+
+	ts.OnRefresh(func(t sdk.Token) {
+		if !t.Expiry.IsZero() {
+			c.SetTokenExpiry(login, t.Expiry)
+		}
+	})
+}
+
+// Middleware возвращает sdk.Middleware, который обновляет метрики по каждому ответу API.
+func (c *Collector) Middleware() sdk.Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return sdk.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			info, _ := sdk.CallInfoFromContext(req.Context())
+
+			resp, err := next.RoundTrip(req)
+			if err != nil {
+				c.failures.WithLabelValues(info.Service, codeTransport).Inc()
+
+				return nil, err //nolint:wrapcheck
+			}
+
+			c.observe(info, resp)
+
+			return resp, nil
+		})
+	}
+}
+
+func (c *Collector) observe(info sdk.CallInfo, resp *http.Response) {
+	login := info.Login
+
+	if units, ok := sdk.ParseUnits(resp.Header); ok {
+		unitsLogin := resp.Header.Get(sdk.HeaderUnitsUsedLogin)
+		if unitsLogin == "" {
+			unitsLogin = login
+		}
+
+		c.unitsRest.WithLabelValues(unitsLogin).Set(float64(units.Rest))
+		c.unitsLimit.WithLabelValues(unitsLogin).Set(float64(units.Limit))
+	}
+
+	if v := resp.Header.Get(sdk.HeaderReportsInQueue); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			c.queueDepth.WithLabelValues(login).Set(float64(n))
+		}
+	}
+
+	if info.Service == "reports" && resp.StatusCode == http.StatusOK && !info.Started.IsZero() {
+		c.reportDuration.WithLabelValues(string(info.ReportType)).Observe(time.Since(info.Started).Seconds())
+	}
+
+	if code, failed := errorCode(info, resp); failed {
+		c.failures.WithLabelValues(info.Service, code).Inc()
+	}
+}
+
+// errorCode определяет код ошибки API по ответу. Тело ответа читается не более maxErrorBody байт и восстанавливается.
+func errorCode(info sdk.CallInfo, resp *http.Response) (string, bool) {
+	isJSON := strings.Contains(resp.Header.Get("Content-Type"), "json")
+	if resp.StatusCode < http.StatusBadRequest && (info.Service == "reports" || !isJSON) {
+		return "", false
+	}
+
+	head, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	resp.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(head), resp.Body), Closer: resp.Body}
+
+	if err == nil {
+		if code := parseErrorCode(head); code != "" {
+			return code, true
+		}
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return strconv.Itoa(resp.StatusCode), true
+	}
+
+	return "", false
+}
+
+// parseErrorCode извлекает error_code из ошибки API Директа или код ошибки OAuth.
+func parseErrorCode(body []byte) string {
+	var data struct {
+		Error json.RawMessage `json:"error"`
+	}
+
+	if json.Unmarshal(body, &data) != nil || len(data.Error) == 0 {
+		return ""
+	}
+
+	var oauthCode string
+	if json.Unmarshal(data.Error, &oauthCode) == nil {
+		return oauthCode
+	}
+
+	var apiErr struct {
+		ErrorCode json.RawMessage `json:"error_code"`
+	}
+	if json.Unmarshal(data.Error, &apiErr) == nil {
+		return strings.Trim(string(apiErr.ErrorCode), `"`)
+	}
+
+	return ""
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+type tokenExpiry struct {
+	desc *prometheus.Desc
+	mu   sync.Mutex
+	at   map[string]time.Time
+}
+
+func newTokenExpiry() *tokenExpiry {
+	return &tokenExpiry{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "oauth_token_expires_in_seconds"),
+			"Время до истечения OAuth-токена по логину.",
+			[]string{labelLogin}, nil,
+		),
+		at: map[string]time.Time{},
+	}
+}
+
+func (t *tokenExpiry) set(login string, expiresAt time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.at[login] = expiresAt
+}
+
+func (t *tokenExpiry) Describe(ch chan<- *prometheus.Desc) {
+	ch <- t.desc
+}
+
+func (t *tokenExpiry) Collect(ch chan<- prometheus.Metric) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for login, at := range t.at {
+		ch <- prometheus.MustNewConstMetric(t.desc, prometheus.GaugeValue, time.Until(at).Seconds(), login)
+	}
+}
@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"net/http"
+	"testing"
+
+	sdk "github.com/mg-realcom/yandex-direct-sdk"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestUnitsByUsedLogin(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		login  string
+	}{
+		{
+			name:   "agency client",
+			header: http.Header{sdk.HeaderUnits: {"10/90/100"}, sdk.HeaderUnitsUsedLogin: {"agency"}},
+			login:  "agency",
+		},
+		{
+			name:   "no used login",
+			header: http.Header{sdk.HeaderUnits: {"10/90/100"}},
+			login:  "client",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			c.observe(sdk.CallInfo{Service: "campaigns", Login: "client"}, &http.Response{StatusCode: http.StatusOK, Header: tt.header})
+
+			if got := testutil.ToFloat64(c.unitsRest.WithLabelValues(tt.login)); got != 90 {
+				t.Errorf("units_rest{login=%q} = %v; want 90", tt.login, got)
+			}
+		})
+	}
+}
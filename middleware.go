@@ -0,0 +1,92 @@
+package yandex_direct_sdk
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// RoundTripperFunc позволяет использовать функцию как http.RoundTripper.
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware оборачивает каждый исходящий запрос клиента: к API Директа, к сервису отчетов и к OAuth.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// Hooks набор обработчиков, вызываемых вокруг исходящего запроса. Любой из них может быть nil.
+type Hooks struct {
+	BeforeRequest func(req *http.Request) error                      // Вызывается перед отправкой. Ошибка прерывает запрос.
+	AfterResponse func(req *http.Request, resp *http.Response) error // Вызывается после получения ответа. Ошибка возвращается вызывающему.
+	OnError       func(req *http.Request, err error)                 // Вызывается при любой ошибке запроса или обработчиков.
+}
+
+// HooksMiddleware создает Middleware из набора обработчиков.
+func HooksMiddleware(h Hooks) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			resp, err := h.roundTrip(next, req)
+			if err != nil && h.OnError != nil {
+				h.OnError(req, err)
+			}
+
+			return resp, err
+		})
+	}
+}
+
+func (h Hooks) roundTrip(next http.RoundTripper, req *http.Request) (*http.Response, error) {
+	if h.BeforeRequest != nil {
+		if err := h.BeforeRequest(req); err != nil {
+			return nil, fmt.Errorf("before request: %w", err)
+		}
+	}
+
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if h.AfterResponse != nil {
+		if err := h.AfterResponse(req, resp); err != nil {
+			resp.Body.Close()
+
+			return nil, fmt.Errorf("after response: %w", err)
+		}
+	}
+
+	return resp, nil
+}
+
+// Use добавляет Middleware в цепочку. Первый добавленный оборачивает все последующие.
+func (c *Client) Use(mw ...Middleware) {
+	c.middlewares = append(c.middlewares, mw...)
+}
+
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	var rt http.RoundTripper = RoundTripperFunc(c.send)
+
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		rt = c.middlewares[i](rt)
+	}
+
+	return rt.RoundTrip(req)
+}
+
+// send выполняет запрос и распаковывает тело ответа, сжатое по явно заданному Accept-Encoding.
+// http.Transport распаковывает ответ сам только если заголовок не задан вызывающим.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	resp, err := c.Tr.Do(req)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	if err := decodeContentEncoding(resp); err != nil {
+		resp.Body.Close()
+
+		return nil, err
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,152 @@
+package yandex_direct_sdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestCopiesDoNotShareMiddlewares(t *testing.T) {
+	var applied []string
+
+	named := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			applied = append(applied, name)
+
+			return next
+		}
+	}
+
+	names := func(mws []Middleware) []string {
+		applied = nil
+		for _, mw := range mws {
+			mw(nil)
+		}
+
+		return applied
+	}
+
+	tests := []struct {
+		name string
+		copy func(c *Client) *Client
+	}{
+		{name: "WithLogin", copy: func(c *Client) *Client { return c.WithLogin("other") }},
+		{name: "WithLanguage", copy: func(c *Client) *Client { return c.WithLanguage("en") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := &Client{middlewares: make([]Middleware, 0, 4)}
+			parent.Use(named("base"))
+
+			sub := tt.copy(parent)
+			sub.Use(named("sub"))
+			parent.Use(named("parent"))
+
+			if got, want := names(sub.middlewares), []string{"base", "sub"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("sub middlewares = %v; want %v", got, want)
+			}
+
+			if got, want := names(parent.middlewares), []string{"base", "parent"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("parent middlewares = %v; want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestHooksMiddleware(t *testing.T) {
+	errHook := errors.New("hook failed")
+	errTransport := errors.New("transport failed")
+
+	tests := []struct {
+		name         string
+		transportErr error
+		beforeErr    error
+		afterErr     error
+		wantErr      error
+		want         []string
+	}{
+		{
+			name: "success",
+			want: []string{"outer before", "inner before", "transport", "inner after", "outer after"},
+		},
+		{
+			name:      "before request aborts the call",
+			beforeErr: errHook,
+			wantErr:   errHook,
+			want:      []string{"outer before", "inner before", "inner error", "outer error"},
+		},
+		{
+			name:         "transport error",
+			transportErr: errTransport,
+			wantErr:      errTransport,
+			want:         []string{"outer before", "inner before", "transport", "inner error", "outer error"},
+		},
+		{
+			name:     "after response error",
+			afterErr: errHook,
+			wantErr:  errHook,
+			want:     []string{"outer before", "inner before", "transport", "inner after", "inner error", "outer error"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var events []string
+
+			hooks := func(name string, beforeErr, afterErr error) Hooks {
+				return Hooks{
+					BeforeRequest: func(*http.Request) error {
+						events = append(events, name+" before")
+
+						return beforeErr
+					},
+					AfterResponse: func(*http.Request, *http.Response) error {
+						events = append(events, name+" after")
+
+						return afterErr
+					},
+					OnError: func(_ *http.Request, err error) {
+						if !errors.Is(err, tt.wantErr) {
+							t.Errorf("%s OnError err = %v; want %v", name, err, tt.wantErr)
+						}
+
+						events = append(events, name+" error")
+					},
+				}
+			}
+
+			tr := RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				events = append(events, "transport")
+				if tt.transportErr != nil {
+					return nil, tt.transportErr
+				}
+
+				return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: http.NoBody, Request: req}, nil
+			})
+
+			c := NewClient(&http.Client{Transport: tr}, "client", nil, &App{}, false, nil)
+			c.Use(HooksMiddleware(hooks("outer", nil, nil)), HooksMiddleware(hooks("inner", tt.beforeErr, tt.afterErr)))
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://example.com", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := c.do(req)
+			if resp != nil {
+				resp.Body.Close()
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v; want %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(events, tt.want) {
+				t.Errorf("events = %v; want %v", events, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package redact
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	Mask            = "[REDACTED]"
+	headerSeparator = "\r\n\r\n"
+)
+
+// Redactor вычищает чувствительные данные из дампов запросов и ответов перед логированием.
+type Redactor struct {
+	headers map[string]struct{}
+	fields  map[string]struct{}
+	emails  bool
+	phones  bool
+
+	jsonRe *regexp.Regexp
+	formRe *regexp.Regexp
+}
+
+// DefaultHeaders заголовки, значения которых скрываются по умолчанию.
+func DefaultHeaders() []string {
+	return []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+}
+
+// DefaultFields поля JSON, форм и query-строк, значения которых скрываются по умолчанию.
+func DefaultFields() []string {
+	return []string{
+		"access_token", "refresh_token", "client_secret", "code", "device_code", "code_verifier",
+		"password", "token", "Email", "Phone",
+	}
+}
+
+var (
+	emailRe = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	phoneRe = regexp.MustCompile(`\+\d[\d\s().\-]{8,16}\d|\b8[\s(\-]+\d{3}[\s)\-]+\d{3}[\s\-]?\d{2}[\s\-]?\d{2}\b`)
+)
+
+// New создает Redactor без правил.
+func New() *Redactor {
+	r := &Redactor{
+		headers: map[string]struct{}{},
+		fields:  map[string]struct{}{},
+	}
+	r.compile()
+
+	return r
+}
+
+// Default создает Redactor, скрывающий токены, секреты, e-mail и телефоны.
+func Default() *Redactor {
+	return New().
+		WithHeaders(DefaultHeaders()...).
+		WithFields(DefaultFields()...).
+		WithEmails(true).
+		WithPhones(true)
+}
+
+// WithHeaders добавляет заголовки, значения которых нужно скрывать.
+func (r *Redactor) WithHeaders(names ...string) *Redactor {
+	for _, name := range names {
+		r.headers[strings.ToLower(name)] = struct{}{}
+	}
+
+	return r
+}
+
+// WithoutHeaders исключает заголовки из списка скрываемых.
+func (r *Redactor) WithoutHeaders(names ...string) *Redactor {
+	for _, name := range names {
+		delete(r.headers, strings.ToLower(name))
+	}
+
+	return r
+}
+
+// WithFields добавляет поля JSON, форм и query-строк, значения которых нужно скрывать.
+func (r *Redactor) WithFields(names ...string) *Redactor {
+	for _, name := range names {
+		r.fields[strings.ToLower(name)] = struct{}{}
+	}
+	r.compile()
+
+	return r
+}
+
+// WithoutFields исключает поля из списка скрываемых.
+func (r *Redactor) WithoutFields(names ...string) *Redactor {
+	for _, name := range names {
+		delete(r.fields, strings.ToLower(name))
+	}
+	r.compile()
+
+	return r
+}
+
+// WithEmails включает или отключает скрытие адресов электронной почты в произвольном тексте.
+func (r *Redactor) WithEmails(enabled bool) *Redactor {
+	r.emails = enabled
+
+	return r
+}
+
+// WithPhones включает или отключает скрытие телефонных номеров в произвольном тексте.
+func (r *Redactor) WithPhones(enabled bool) *Redactor {
+	r.phones = enabled
+
+	return r
+}
+
+func (r *Redactor) compile() {
+	if len(r.fields) == 0 {
+		r.jsonRe, r.formRe = nil, nil
+
+		return
+	}
+
+	names := make([]string, 0, len(r.fields))
+	for name := range r.fields {
+		names = append(names, regexp.QuoteMeta(name))
+	}
+
+	alt := strings.Join(names, "|")
+	r.jsonRe = regexp.MustCompile(`(?i)("(?:` + alt + `)"\s*:\s*)("(?:[^"\\]|\\.)*"|[^,}\]\s]+)`)
+	r.formRe = regexp.MustCompile(`(?i)((?:^|[?&\s])(?:` + alt + `)=)([^&\s]*)`)
+}
+
+// Dump скрывает чувствительные данные в дампе, полученном из httputil.DumpRequestOut или httputil.DumpResponse.
+func (r *Redactor) Dump(dump []byte) []byte {
+	if r == nil || len(dump) == 0 {
+		return dump
+	}
+
+	head, body, found := strings.Cut(string(dump), headerSeparator)
+
+	lines := strings.Split(head, "\r\n")
+	for i := 1; i < len(lines); i++ {
+		lines[i] = r.header(lines[i])
+	}
+
+	out := strings.Join(lines, "\r\n")
+	if found {
+		out += headerSeparator + body
+	}
+
+	return []byte(r.String(out))
+}
+
+// String скрывает чувствительные поля, e-mail и телефоны в произвольном тексте.
+func (r *Redactor) String(s string) string {
+	if r == nil {
+		return s
+	}
+
+	if r.jsonRe != nil {
+		s = r.jsonRe.ReplaceAllString(s, `${1}"`+Mask+`"`)
+		s = r.formRe.ReplaceAllString(s, "${1}"+Mask)
+	}
+
+	if r.emails {
+		s = emailRe.ReplaceAllString(s, Mask)
+	}
+
+	if r.phones {
+		s = phoneRe.ReplaceAllString(s, Mask)
+	}
+
+	return s
+}
+
+func (r *Redactor) header(line string) string {
+	name, _, found := strings.Cut(line, ":")
+	if !found {
+		return line
+	}
+
+	if _, ok := r.headers[strings.ToLower(strings.TrimSpace(name))]; !ok {
+		return line
+	}
+
+	return name + ": " + Mask
+}
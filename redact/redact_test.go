@@ -0,0 +1,105 @@
+package redact
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDumpHidesSecrets(t *testing.T) {
+	tests := []struct {
+		name    string
+		dump    string
+		secrets []string
+		keep    []string
+	}{
+		{
+			name:    "authorization header",
+			dump:    "GET /json/v5/reports HTTP/1.1\r\nHost: api.direct.yandex.com\r\nAuthorization: Bearer y0_AgAAAAtoken\r\nClient-Login: client\r\n\r\n",
+			secrets: []string{"y0_AgAAAAtoken"},
+			keep:    []string{"Client-Login: client", "Authorization: " + Mask},
+		},
+		{
+			name:    "oauth header case",
+			dump:    "GET /info HTTP/1.1\r\nauthorization: OAuth secret-oauth\r\n\r\n",
+			secrets: []string{"secret-oauth"},
+		},
+		{
+			name:    "client_secret and code in form body",
+			dump:    "POST /token HTTP/1.1\r\nContent-Type: application/x-www-form-urlencoded\r\n\r\nclient_id=app&client_secret=s3cr3t&code=7654321&grant_type=authorization_code",
+			secrets: []string{"s3cr3t", "7654321"},
+			keep:    []string{"client_id=app", "grant_type=authorization_code"},
+		},
+		{
+			name:    "refresh_token in form body",
+			dump:    "POST /token HTTP/1.1\r\n\r\ngrant_type=refresh_token&refresh_token=1:refresh:abc&client_id=app",
+			secrets: []string{"1:refresh:abc"},
+			keep:    []string{"grant_type=refresh_token"},
+		},
+		{
+			name:    "device code and verifier in form body",
+			dump:    "POST /token HTTP/1.1\r\n\r\ngrant_type=device_code&code=dev-code&code_verifier=verifier-value",
+			secrets: []string{"dev-code", "verifier-value"},
+		},
+		{
+			name:    "tokens in json body",
+			dump:    "HTTP/1.1 200 OK\r\nContent-Type: application/json\r\n\r\n{\"token_type\": \"bearer\", \"access_token\": \"y0_access\", \"refresh_token\":\"1:refresh\", \"expires_in\": 31536000}",
+			secrets: []string{"y0_access", "1:refresh"},
+			keep:    []string{`"token_type": "bearer"`, `"expires_in": 31536000`},
+		},
+		{
+			name:    "escaped quotes in json value",
+			dump:    "HTTP/1.1 200 OK\r\n\r\n{\"access_token\":\"a\\\"b-secret\"}",
+			secrets: []string{"b-secret"},
+		},
+		{
+			name:    "token in query string",
+			dump:    "GET /callback?state=xyz&code=query-code HTTP/1.1\r\n\r\n",
+			secrets: []string{"query-code"},
+			keep:    []string{"state=xyz"},
+		},
+		{
+			name:    "email and phone",
+			dump:    "HTTP/1.1 200 OK\r\n\r\n{\"result\":{\"Clients\":[{\"Login\":\"client\",\"Notification\":{\"Email\":\"user@example.com\"},\"Phone\":\"+7 (495) 123-45-67\"}]}}",
+			secrets: []string{"user@example.com", "123-45-67"},
+			keep:    []string{`"Login":"client"`},
+		},
+	}
+
+	r := Default()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(r.Dump([]byte(tt.dump)))
+
+			for _, s := range tt.secrets {
+				if strings.Contains(got, s) {
+					t.Errorf("secret %q leaked:\n%s", s, got)
+				}
+			}
+
+			for _, s := range tt.keep {
+				if !strings.Contains(got, s) {
+					t.Errorf("expected %q to be kept:\n%s", s, got)
+				}
+			}
+		})
+	}
+}
+
+func TestWithoutFields(t *testing.T) {
+	r := Default().WithoutFields("code")
+
+	got := r.String("state=1&code=visible&client_secret=hidden")
+	if !strings.Contains(got, "code=visible") || strings.Contains(got, "hidden") {
+		t.Errorf("unexpected result %q", got)
+	}
+}
+
+func TestNilRedactor(t *testing.T) {
+	var r *Redactor
+
+	dump := []byte("GET / HTTP/1.1\r\nAuthorization: Bearer x\r\n\r\n")
+	if got := r.Dump(dump); string(got) != string(dump) {
+		t.Errorf("nil redactor changed dump: %q", got)
+	}
+}
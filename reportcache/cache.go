@@ -0,0 +1,259 @@
+package reportcache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mg-realcom/yandex-direct-sdk/statistics"
+)
+
+const (
+	DefaultTTL = 24 * time.Hour
+
+	manifestName = "manifest.json"
+	filePerm     = 0o644
+	dirPerm      = 0o755
+)
+
+// Cache хранит файлы отчетов на диске, по каталогу на ключ.
+type Cache struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+}
+
+// Entry описание отчета в кэше.
+type Entry struct {
+	Key        string                `json:"key"`
+	Login      string                `json:"login"`
+	ReportType statistics.ReportType `json:"report_type"`
+	DateFrom   string                `json:"date_from"`
+	DateTo     string                `json:"date_to"`
+	CreatedAt  time.Time             `json:"created_at"`
+	Files      []string              `json:"files"` // Имена файлов частей внутри каталога записи.
+}
+
+// New создает кэш в каталоге dir. Нулевой ttl заменяется на DefaultTTL.
+func New(dir string, ttl time.Duration) (*Cache, error) {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+
+	if err := os.MkdirAll(dir, dirPerm); err != nil {
+		return nil, fmt.Errorf("create cache dir: %w", err)
+	}
+
+	return &Cache{dir: dir, ttl: ttl, now: time.Now}, nil
+}
+
+// Cacheable сообщает, можно ли кэшировать отчет. Кэшируются только отчеты за произвольный период,
+// который заканчивается раньше вчерашнего дня: статистика за сегодня и вчера еще меняется.
+func (c *Cache) Cacheable(def statistics.ReportDefinition) bool {
+	if def.DateRangeType != statistics.DateRangeCustomDate || def.Selection == nil {
+		return false
+	}
+
+	to, err := time.ParseInLocation(statistics.DateLayout, def.Selection.DateTo, statistics.Moscow)
+	if err != nil {
+		return false
+	}
+
+	now := c.now().In(statistics.Moscow)
+	yesterday := time.Date(now.Year(), now.Month(), now.Day()-1, 0, 0, 0, 0, statistics.Moscow)
+
+	return to.Before(yesterday)
+}
+
+func (c *Cache) entryDir(key string) string {
+	return filepath.Join(c.dir, key)
+}
+
+// Get возвращает пути к файлам отчета. ok=false, если записи нет, срок ее хранения истек или файлы повреждены.
+func (c *Cache) Get(key string) ([]string, bool, error) {
+	data, err := os.ReadFile(filepath.Join(c.entryDir(key), manifestName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, fmt.Errorf("read manifest: %w", err)
+	}
+
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, false, nil //nolint:nilerr
+	}
+
+	if c.now().Sub(e.CreatedAt) > c.ttl {
+		return nil, false, nil
+	}
+
+	paths := make([]string, 0, len(e.Files))
+
+	for _, name := range e.Files {
+		path := filepath.Join(c.entryDir(key), name)
+		if _, err := os.Stat(path); err != nil {
+			return nil, false, nil //nolint:nilerr
+		}
+
+		paths = append(paths, path)
+	}
+
+	return paths, true, nil
+}
+
+// Put копирует файлы отчета в кэш. Запись появляется атомарно: файлы готовятся во временном
+// каталоге, который затем переименовывается. Действующая запись с тем же ключом не заменяется:
+// ее записал параллельный Put того же отчета, и читатели могут копировать ее файлы.
+func (c *Cache) Put(e Entry, files []string) error {
+	tmp, err := os.MkdirTemp(c.dir, ".report_*")
+	if err != nil {
+		return fmt.Errorf("create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	e.CreatedAt = c.now()
+	e.Files = make([]string, 0, len(files))
+
+	for i, src := range files {
+		name := fmt.Sprintf("part_%d%s", i+1, fileExt(src))
+		if err := copyFile(src, filepath.Join(tmp, name)); err != nil {
+			return err
+		}
+
+		e.Files = append(e.Files, name)
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal manifest: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmp, manifestName), data, filePerm); err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+
+	if err := os.Chmod(tmp, dirPerm); err != nil {
+		return fmt.Errorf("chmod %s: %w", e.Key, err)
+	}
+
+	dst := c.entryDir(e.Key)
+
+	err = os.Rename(tmp, dst)
+
+	switch {
+	case err == nil:
+		return nil
+	case !errors.Is(err, os.ErrExist):
+		return fmt.Errorf("rename %s: %w", e.Key, err)
+	}
+
+	if _, ok, _ := c.Get(e.Key); ok {
+		return nil
+	}
+
+	// Устаревшая или поврежденная запись сначала убирается из-под ключа и только потом удаляется,
+	// поэтому ключ ни в какой момент не указывает на наполовину удаленный каталог.
+	stale := tmp + ".stale"
+	if err := os.Rename(dst, stale); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("move old entry %s: %w", e.Key, err)
+	}
+	defer os.RemoveAll(stale)
+
+	if err := os.Rename(tmp, dst); err != nil && !errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("rename %s: %w", e.Key, err)
+	}
+
+	return nil
+}
+
+// Prune удаляет записи с истекшим сроком хранения и поврежденные записи.
+func (c *Cache) Prune() error {
+	items, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("read cache dir: %w", err)
+	}
+
+	for _, item := range items {
+		if !item.IsDir() {
+			continue
+		}
+
+		if _, ok, err := c.Get(item.Name()); err == nil && ok {
+			continue
+		}
+
+		if err := os.RemoveAll(filepath.Join(c.dir, item.Name())); err != nil {
+			return fmt.Errorf("remove %s: %w", item.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// Restore копирует файлы записи в dir под новыми именами, чтобы вызывающий мог изменять и удалять их,
+// не затрагивая кэш. prefix задает начало имени файла по номеру части, расширение сохраняется.
+func Restore(paths []string, dir string, prefix func(part int) string) ([]string, error) {
+	out := make([]string, 0, len(paths))
+
+	for i, src := range paths {
+		f, err := os.CreateTemp(dir, prefix(i+1)+"_*"+fileExt(src))
+		if err != nil {
+			return out, fmt.Errorf("failed to create file: %w", err)
+		}
+
+		name := f.Name()
+		f.Close()
+
+		if err := copyFile(src, name); err != nil {
+			_ = os.Remove(name)
+
+			return out, err
+		}
+
+		out = append(out, name)
+	}
+
+	return out, nil
+}
+
+// fileExt возвращает расширение файла отчета вместе с расширением сжатия, например .tsv.gz.
+func fileExt(name string) string {
+	ext := filepath.Ext(name)
+	if ext == ".gz" || ext == ".zst" {
+		return filepath.Ext(strings.TrimSuffix(name, ext)) + ext
+	}
+
+	return ext
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", src, err)
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, filePerm)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", dst, err)
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+
+		return fmt.Errorf("copy %s: %w", src, err)
+	}
+
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("close %s: %w", dst, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,267 @@
+package reportcache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mg-realcom/yandex-direct-sdk/statistics"
+)
+
+// testNow 01:00 по Москве 10 марта: в UTC это еще 9 марта.
+var testNow = time.Date(2024, 3, 10, 1, 0, 0, 0, statistics.Moscow).In(time.UTC)
+
+func newTestCache(t *testing.T) *Cache {
+	t.Helper()
+
+	c, err := New(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.now = func() time.Time { return testNow }
+
+	return c
+}
+
+func writeParts(t *testing.T, contents ...string) []string {
+	t.Helper()
+
+	dir := t.TempDir()
+	paths := make([]string, 0, len(contents))
+
+	for i, content := range contents {
+		path := filepath.Join(dir, fmt.Sprintf("report_part_%d_123.tsv.gz", i+1))
+		if err := os.WriteFile(path, []byte(content), filePerm); err != nil {
+			t.Fatal(err)
+		}
+
+		paths = append(paths, path)
+	}
+
+	return paths
+}
+
+func TestCacheable(t *testing.T) {
+	custom := func(to string) statistics.ReportDefinition {
+		return statistics.ReportDefinition{
+			DateRangeType: statistics.DateRangeCustomDate,
+			Selection:     &statistics.SelectionCriteria{DateFrom: "2024-03-01", DateTo: to},
+		}
+	}
+
+	tests := []struct {
+		name string
+		def  statistics.ReportDefinition
+		want bool
+	}{
+		{name: "today", def: custom("2024-03-10")},
+		{name: "yesterday", def: custom("2024-03-09")},
+		{name: "day before yesterday", def: custom("2024-03-08"), want: true},
+		{name: "last month", def: custom("2024-02-29"), want: true},
+		{name: "invalid date", def: custom("08.03.2024")},
+		{name: "no selection", def: statistics.ReportDefinition{DateRangeType: statistics.DateRangeCustomDate}},
+		{name: "predefined period", def: statistics.ReportDefinition{DateRangeType: statistics.DateRangeLastWeek, Selection: &statistics.SelectionCriteria{}}},
+	}
+
+	c := newTestCache(t)
+
+	for _, tt := range tests {
+		if got := c.Cacheable(tt.def); got != tt.want {
+			t.Errorf("%s: Cacheable() = %v; want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGetTTL(t *testing.T) {
+	c := newTestCache(t)
+
+	if err := c.Put(Entry{Key: "k"}, writeParts(t, "a", "b")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		elapsed time.Duration
+		ok      bool
+	}{
+		{elapsed: 0, ok: true},
+		{elapsed: time.Hour, ok: true},
+		{elapsed: time.Hour + time.Second},
+	}
+
+	for _, tt := range tests {
+		c.now = func() time.Time { return testNow.Add(tt.elapsed) }
+
+		paths, ok, err := c.Get("k")
+		if err != nil || ok != tt.ok {
+			t.Errorf("after %v: Get() = %v, %v; want %v", tt.elapsed, ok, err, tt.ok)
+		}
+
+		if ok && len(paths) != 2 {
+			t.Errorf("after %v: Get() returned %d paths; want 2", tt.elapsed, len(paths))
+		}
+	}
+}
+
+func TestPrune(t *testing.T) {
+	c := newTestCache(t)
+
+	c.now = func() time.Time { return testNow.Add(-2 * time.Hour) }
+	if err := c.Put(Entry{Key: "expired"}, writeParts(t, "a")); err != nil {
+		t.Fatal(err)
+	}
+
+	c.now = func() time.Time { return testNow }
+	if err := c.Put(Entry{Key: "valid"}, writeParts(t, "a")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Put(Entry{Key: "missing_part"}, writeParts(t, "a")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Remove(filepath.Join(c.dir, "missing_part", "part_1.tsv.gz")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(c.dir, "corrupted"), dirPerm); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(c.dir, "corrupted", manifestName), []byte("{"), filePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Prune(); err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := os.ReadDir(c.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, item := range items {
+		names = append(names, item.Name())
+	}
+
+	if strings.Join(names, ",") != "valid" {
+		t.Errorf("entries after Prune = %v; want [valid]", names)
+	}
+}
+
+func TestPutKeepsValidEntry(t *testing.T) {
+	const writers = 10
+
+	c := newTestCache(t)
+	parts := writeParts(t, "a", "b")
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if err := c.Put(Entry{Key: "k"}, parts); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if _, ok, err := c.Get("k"); !ok || err != nil {
+		t.Fatalf("Get() = %v, %v; want entry", ok, err)
+	}
+
+	items, err := os.ReadDir(c.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 1 {
+		t.Errorf("cache dir has %d items; want only the entry, temp dirs must be removed", len(items))
+	}
+}
+
+func TestPutReplacesExpiredEntry(t *testing.T) {
+	c := newTestCache(t)
+
+	c.now = func() time.Time { return testNow.Add(-2 * time.Hour) }
+	if err := c.Put(Entry{Key: "k"}, writeParts(t, "old")); err != nil {
+		t.Fatal(err)
+	}
+
+	c.now = func() time.Time { return testNow }
+	if err := c.Put(Entry{Key: "k"}, writeParts(t, "new")); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, ok, err := c.Get("k")
+	if err != nil || !ok {
+		t.Fatalf("Get() = %v, %v; want entry", ok, err)
+	}
+
+	data, err := os.ReadFile(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != "new" {
+		t.Errorf("part = %q; want new", data)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	c := newTestCache(t)
+
+	if err := c.Put(Entry{Key: "k"}, writeParts(t, "a", "b")); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, _, err := c.Get("k")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+
+	restored, err := Restore(paths, dir, func(part int) string {
+		return fmt.Sprintf("report_part_%d", part)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, path := range restored {
+		name := regexp.MustCompile(fmt.Sprintf(`^report_part_%d_\d+\.tsv\.gz$`, i+1))
+		if filepath.Dir(path) != dir || !name.MatchString(filepath.Base(path)) {
+			t.Errorf("restored part %d = %s", i+1, path)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want := []string{"a", "b"}[i]; string(data) != want {
+			t.Errorf("part %d = %q; want %q", i+1, data, want)
+		}
+	}
+
+	if err := os.Remove(restored[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok, _ := c.Get("k"); !ok {
+		t.Error("removing a restored file affected the cache")
+	}
+}
@@ -0,0 +1,155 @@
+package yandex_direct_sdk
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/mg-realcom/yandex-direct-sdk/statistics"
+)
+
+var ErrNoReportPeriod = errors.New("report period is missing in report title")
+
+// reportTitle строка с названием и периодом отчета, которую Директ добавляет без заголовка skipReportHeader.
+var reportTitle = regexp.MustCompile(`\((\d{4}-\d{2}-\d{2}) - (\d{4}-\d{2}-\d{2})\)\s*$`) //nolint:gochecknoglobals
+
+// ReportReader построчно читает TSV-файл отчета. Первая строка файла — названия столбцов;
+// строка с названием и периодом отчета перед ней, если есть, пропускается.
+type ReportReader struct {
+	file   *os.File
+	dec    io.ReadCloser
+	reader *bufio.Reader
+	header []string
+	period statistics.DateRange
+}
+
+// OpenReport открывает файл отчета и читает строку с названиями столбцов. Файлы, сжатые gzip или zstd,
+// распаковываются прозрачно; сжатие определяется по содержимому, а не по расширению.
+func OpenReport(path string) (*ReportReader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open report: %w", err)
+	}
+
+	dec, err := decompressReader(bufio.NewReader(f))
+	if err != nil {
+		f.Close()
+
+		return nil, fmt.Errorf("open report: %w", err)
+	}
+
+	r := &ReportReader{file: f, dec: dec, reader: bufio.NewReader(dec)}
+
+	r.header, err = r.Next()
+	if err == nil && len(r.header) == 1 {
+		if m := reportTitle.FindStringSubmatch(r.header[0]); m != nil {
+			r.period = statistics.DateRange{From: m[1], To: m[2]}
+			r.header, err = r.Next()
+		}
+	}
+
+	if err != nil && !errors.Is(err, io.EOF) {
+		r.Close()
+
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	return r, nil
+}
+
+// Header возвращает названия столбцов.
+func (r *ReportReader) Header() []string {
+	return r.header
+}
+
+// Period возвращает период отчета из строки с названием отчета. False, если такой строки в файле нет.
+func (r *ReportReader) Period() (statistics.DateRange, bool) {
+	return r.period, r.period.From != ""
+}
+
+// Next возвращает следующую строку отчета или io.EOF.
+func (r *ReportReader) Next() ([]string, error) {
+	for {
+		line, err := r.reader.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("read line: %w", err)
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			if err != nil {
+				return nil, io.EOF
+			}
+
+			continue
+		}
+
+		return strings.Split(line, "\t"), nil
+	}
+}
+
+func (r *ReportReader) Close() error {
+	_ = r.dec.Close()
+
+	return r.file.Close() //nolint:wrapcheck
+}
+
+// ReadReports последовательно читает части отчета и вызывает fn для каждой строки.
+func ReadReports(paths []string, fn func(header, row []string) error) error {
+	for _, path := range paths {
+		if err := readReport(path, fn); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+func readReport(path string, fn func(header, row []string) error) error {
+	r, err := OpenReport(path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for {
+		row, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := fn(r.Header(), row); err != nil {
+			return err
+		}
+	}
+}
+
+// readReportPeriod читает период отчета из строки с названием в начале файла.
+func readReportPeriod(path string) (statistics.DateRange, bool) {
+	r, err := OpenReport(path)
+	if err != nil {
+		return statistics.DateRange{}, false
+	}
+	defer r.Close()
+
+	return r.Period()
+}
+
+// ColumnIndex возвращает номер столбца name или -1.
+func ColumnIndex(header []string, name string) int {
+	for i, h := range header {
+		if h == name {
+			return i
+		}
+	}
+
+	return -1
+}
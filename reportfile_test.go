@@ -0,0 +1,68 @@
+package yandex_direct_sdk
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/mg-realcom/yandex-direct-sdk/statistics"
+)
+
+func TestOpenReportTitle(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		header  []string
+		period  statistics.DateRange
+		ok      bool
+	}{
+		{
+			name:    "title",
+			content: "daily (2024-01-01 - 2024-01-07)\nDate\tClicks\n2024-01-01\t1\n",
+			header:  []string{"Date", "Clicks"},
+			period:  statistics.DateRange{From: "2024-01-01", To: "2024-01-07"},
+			ok:      true,
+		},
+		{
+			name:    "quoted title",
+			content: "\"daily\" (2024-01-01 - 2024-01-07)\r\nDate\tClicks\r\n",
+			header:  []string{"Date", "Clicks"},
+			period:  statistics.DateRange{From: "2024-01-01", To: "2024-01-07"},
+			ok:      true,
+		},
+		{
+			name:    "no title",
+			content: "Date\tClicks\n2024-01-01\t1\n",
+			header:  []string{"Date", "Clicks"},
+		},
+		{
+			name:    "single column",
+			content: "Date\n2024-01-01\n",
+			header:  []string{"Date"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "report.tsv")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			r, err := OpenReport(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+
+			if !reflect.DeepEqual(r.Header(), tt.header) {
+				t.Errorf("Header() = %v; want %v", r.Header(), tt.header)
+			}
+
+			if period, ok := r.Period(); period != tt.period || ok != tt.ok {
+				t.Errorf("Period() = %v, %v; want %v, %v", period, ok, tt.period, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package yandex_direct_sdk
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mg-realcom/yandex-direct-sdk/statistics"
+)
+
+// ErrCodeInvalidReportParams код ошибки сервиса Reports «неверные параметры запроса». Им же сервис отвечает
+// на повторное использование имени отчета с другими параметрами: отдельного кода или кода подробностей
+// для конфликта имен нет, различается только текст error_detail на языке запроса.
+const ErrCodeInvalidReportParams = 4000
+
+const (
+	// reportNameHashLen количество символов хеша определения в имени отчета.
+	reportNameHashLen = 12
+	// maxConflictRenames количество повторов запроса с новым именем после ошибки ErrCodeInvalidReportParams.
+	maxConflictRenames = 2
+)
+
+// NameConflictPolicy действие при ошибке «отчет с таким именем уже существует с другими параметрами».
+//
+// Уже сформированный под этим именем отчет переиспользовать нельзя: Reports отдает отчет только в ответ
+// на запрос с теми же параметрами, а параметры существующего отчета неизвестны и по определению отличаются
+// от запрошенных. Отчеты с теми же параметрами переиспользуются без конфликта благодаря ReportName.
+type NameConflictPolicy int
+
+const (
+	RenameOnConflict NameConflictPolicy = iota // Повторить запрос с именем, дополненным номером попытки.
+	FailOnConflict                             // Вернуть ошибку.
+)
+
+// SetNameConflictPolicy задает действие при конфликте имен отчетов. По умолчанию RenameOnConflict.
+func (c *Client) SetNameConflictPolicy(p NameConflictPolicy) {
+	c.nameConflict = p
+}
+
+// ReportName возвращает детерминированное имя отчета: логин, префикс и начало хеша определения.
+// Повторный запрос того же определения получает то же имя, и Директ возвращает уже сформированный отчет
+// вместо постановки нового в очередь.
+func (c *Client) ReportName(prefix string, def statistics.ReportDefinition) string {
+	hash := statistics.Hash(def, c.Login, c.reportHeaders())[:reportNameHashLen]
+
+	parts := make([]string, 0, 3)
+	for _, p := range []string{c.Login, prefix, hash} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+
+	return strings.Join(parts, "_")
+}
+
+// conflictName имя отчета для попытки attempt после конфликта имен. Имя строится от исходного имени
+// и отличается для каждой попытки, поэтому повтор не упирается в тот же конфликт.
+func conflictName(name string, attempt int) string {
+	return fmt.Sprintf("%s_r%d", name, attempt)
+}
+
+// IsReportNameConflict сообщает, что ошибка может означать конфликт имен: отчет с таким именем уже
+// сформирован или стоит в очереди с другими параметрами. Проверяется только код ошибки
+// (см. ErrCodeInvalidReportParams), текст подробностей зависит от языка и не проверяется. Поэтому при
+// RenameOnConflict любая ошибка параметров отчета приводит к повтору с новым именем, и если параметры
+// действительно неверны, ошибка возвращается после повторов.
+func IsReportNameConflict(err error) bool {
+	var apiErr *APIError
+
+	return errors.As(err, &apiErr) && apiErr.Code() == ErrCodeInvalidReportParams
+}
@@ -0,0 +1,138 @@
+package yandex_direct_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// Ответы с ошибками в формате сервисов Директа: Reports возвращает error_code строкой, JSON API — числом.
+const (
+	reportConflictRU = `{"error":{"request_id":"1234567890","error_code":"4000","error_string":"Неверные параметры запроса",` +
+		`"error_detail":"Отчет с таким названием уже существует и сформирован с другими параметрами"}}`
+	reportConflictEN = `{"error":{"request_id":"1234567891","error_code":"4000","error_string":"Invalid request parameters",` +
+		`"error_detail":"A report with the same name but different parameters was already created"}}`
+	reportBadFormat = `{"error":{"request_id":"1234567892","error_code":"4001","error_string":"Неверный формат запроса",` +
+		`"error_detail":"Не указан обязательный параметр DateRangeType"}}`
+	apiNotEnoughUnits = `{"error":{"request_id":"1234567893","error_code":152,"error_string":"Недостаточно баллов",` +
+		`"error_detail":"Для выполнения операции не хватает баллов"}}`
+)
+
+func fixtureError(t *testing.T, body string) error {
+	t.Helper()
+
+	var data apiResponse
+	if err := json.Unmarshal([]byte(body), &data); err != nil {
+		t.Fatal(err)
+	}
+
+	return fmt.Errorf("ошибка отчета: %w", data.Error)
+}
+
+func TestIsReportNameConflict(t *testing.T) {
+	tests := []struct {
+		name string
+		err  func(t *testing.T) error
+		want bool
+	}{
+		{name: "conflict ru", err: func(t *testing.T) error { return fixtureError(t, reportConflictRU) }, want: true},
+		{name: "conflict en", err: func(t *testing.T) error { return fixtureError(t, reportConflictEN) }, want: true},
+		{name: "invalid format", err: func(t *testing.T) error { return fixtureError(t, reportBadFormat) }},
+		{name: "json api error", err: func(t *testing.T) error { return fixtureError(t, apiNotEnoughUnits) }},
+		{name: "not api error", err: func(_ *testing.T) error { return errors.New("4000") }},
+		{name: "nil", err: func(_ *testing.T) error { return nil }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsReportNameConflict(tt.err(t)); got != tt.want {
+				t.Errorf("IsReportNameConflict() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConflictName(t *testing.T) {
+	c, _ := newLoggedClient(&stubTransport{})
+	name := c.ReportName("daily", testReportDefinition(10))
+
+	seen := map[string]bool{name: true}
+
+	for attempt := 1; attempt <= maxConflictRenames; attempt++ {
+		renamed := conflictName(name, attempt)
+		if seen[renamed] {
+			t.Errorf("conflictName(%q, %d) = %q repeats an earlier name", name, attempt, renamed)
+		}
+
+		seen[renamed] = true
+	}
+}
+
+func TestGetFilesNameConflict(t *testing.T) {
+	const page = "Date\tCampaignId\tConversions_12345_LSC\n2024-01-01\t1\t2\n"
+
+	conflict := stubResponse{status: http.StatusBadRequest, header: http.Header{"Content-Type": {"application/json"}}, body: reportConflictRU}
+
+	tests := []struct {
+		name      string
+		policy    NameConflictPolicy
+		responses []stubResponse
+		names     []string
+		conflict  bool
+	}{
+		{
+			name:      "renamed",
+			responses: []stubResponse{conflict, {status: http.StatusOK, body: page}},
+			names:     []string{"test_part_1", "test_r1_part_1"},
+		},
+		{
+			name:      "renamed twice",
+			responses: []stubResponse{conflict, conflict, {status: http.StatusOK, body: page}},
+			names:     []string{"test_part_1", "test_r1_part_1", "test_r2_part_1"},
+		},
+		{
+			name:      "persistent error",
+			responses: []stubResponse{conflict},
+			names:     []string{"test_part_1", "test_r1_part_1", "test_r2_part_1"},
+			conflict:  true,
+		},
+		{
+			name:      "fail policy",
+			policy:    FailOnConflict,
+			responses: []stubResponse{conflict, {status: http.StatusOK, body: page}},
+			names:     []string{"test_part_1"},
+			conflict:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &bodyRecorder{next: &stubTransport{responses: tt.responses}}
+			c, _ := newLoggedClient(rec)
+			c.SetNameConflictPolicy(tt.policy)
+
+			_, err := c.GetFiles(context.Background(), t.TempDir(), testReportDefinition(10))
+			if IsReportNameConflict(err) != tt.conflict {
+				t.Fatalf("GetFiles() error = %v; want conflict %v", err, tt.conflict)
+			}
+
+			var names []string
+
+			for _, body := range rec.bodies {
+				var req Request
+				if err := json.Unmarshal([]byte(body), &req); err != nil {
+					t.Fatal(err)
+				}
+
+				names = append(names, req.Params.ReportName)
+			}
+
+			if fmt.Sprint(names) != fmt.Sprint(tt.names) {
+				t.Errorf("report names = %v; want %v", names, tt.names)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package yandex_direct_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mg-realcom/yandex-direct-sdk/common"
+	"github.com/mg-realcom/yandex-direct-sdk/reportcache"
+	"github.com/mg-realcom/yandex-direct-sdk/statistics"
+)
+
+func testReportDefinition(limit int) statistics.ReportDefinition {
+	return statistics.ReportDefinition{
+		FieldNames:    []string{"Date", "CampaignId", "Conversions_12345_LSC"},
+		Page:          &common.Page{Limit: limit},
+		ReportName:    "test",
+		ReportType:    statistics.CampaignPerformanceReport,
+		DateRangeType: statistics.DateRangeAuto,
+		Format:        common.FormatTSV,
+	}
+}
+
+func TestGetFilesPaging(t *testing.T) {
+	const header = "Date\tCampaignId\tConversions_12345_LSC\n"
+
+	tests := []struct {
+		name     string
+		pages    []string
+		files    int
+		requests int
+	}{
+		{
+			name:     "last page is short",
+			pages:    []string{header + "2024-01-01\t1\t2\n2024-01-01\t2\t3\n", header + "2024-01-02\t1\t4\n"},
+			files:    2,
+			requests: 2,
+		},
+		{
+			name:     "rows shorter than header",
+			pages:    []string{header + "1\t1\t1\n2\t2\t2\n", header},
+			files:    1,
+			requests: 2,
+		},
+		{
+			name:     "empty report",
+			pages:    []string{header},
+			files:    0,
+			requests: 1,
+		},
+		{
+			name:     "no trailing newline",
+			pages:    []string{header + "2024-01-01\t1\t2"},
+			files:    1,
+			requests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubTransport{}
+			for _, page := range tt.pages {
+				stub.responses = append(stub.responses, stubResponse{status: http.StatusOK, body: page})
+			}
+
+			c, _ := newLoggedClient(stub)
+
+			files, err := c.GetFiles(context.Background(), t.TempDir(), testReportDefinition(2))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(files) != tt.files {
+				t.Errorf("files = %d; want %d", len(files), tt.files)
+			}
+
+			if len(stub.requests) != tt.requests {
+				t.Errorf("requests = %d; want %d", len(stub.requests), tt.requests)
+			}
+		})
+	}
+}
+
+func TestGetFilesPageOffsets(t *testing.T) {
+	const header = "Date\tCampaignId\tConversions_12345_LSC\n"
+
+	rec := &bodyRecorder{next: &stubTransport{responses: []stubResponse{
+		{status: http.StatusOK, body: header + "1\t1\t1\n2\t2\t2\n"},
+		{status: http.StatusOK, body: header + "3\t3\t3\n"},
+	}}}
+	c, _ := newLoggedClient(rec)
+
+	if _, err := c.GetFiles(context.Background(), t.TempDir(), testReportDefinition(2)); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, want := range []int{0, 2} {
+		var req Request
+		if err := json.Unmarshal([]byte(rec.bodies[i]), &req); err != nil {
+			t.Fatal(err)
+		}
+
+		if req.Params.Page.Offset != want {
+			t.Errorf("request %d offset = %d; want %d", i+1, req.Params.Page.Offset, want)
+		}
+	}
+}
+
+func TestGetFilesReportCacheHit(t *testing.T) {
+	const body = "Date\tCampaignId\tConversions_12345_LSC\n2024-01-01\t1\t2\n"
+
+	stub := &stubTransport{responses: []stubResponse{{status: http.StatusOK, body: body}}}
+	c, _ := newLoggedClient(stub)
+
+	cache, err := reportcache.New(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.SetReportCache(cache)
+
+	def := testReportDefinition(10)
+	def.DateRangeType = statistics.DateRangeCustomDate
+	def.Selection = &statistics.SelectionCriteria{DateFrom: "2024-01-01", DateTo: "2024-01-02"}
+
+	first, err := c.GetFiles(context.Background(), t.TempDir(), def)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+
+	second, err := c.GetFiles(context.Background(), dir, def)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stub.requests) != 1 {
+		t.Errorf("requests = %d; want 1, the second report must come from the cache", len(stub.requests))
+	}
+
+	if len(first) != 1 || len(second) != 1 || filepath.Dir(second[0]) != dir {
+		t.Fatalf("files = %v and %v", first, second)
+	}
+
+	want, err := os.ReadFile(first[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(second[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != string(want) {
+		t.Errorf("cached part = %q; want %q", got, want)
+	}
+}
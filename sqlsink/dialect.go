@@ -0,0 +1,179 @@
+package sqlsink
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mg-realcom/yandex-direct-sdk/statistics"
+)
+
+// Dialect диалект SQL базы назначения.
+type Dialect string
+
+const (
+	// Postgres загружает строки командой COPY ... FROM STDIN через подготовленный запрос,
+	// как это поддерживает драйвер lib/pq (pq.CopyIn).
+	Postgres Dialect = "postgres"
+	// ClickHouse загружает строки пакетной вставкой драйвера clickhouse-go: Prepare, Exec на каждую строку,
+	// Commit отправляет пакет. Строки сначала вставляются в промежуточную таблицу, затем период удаляется
+	// запросом DELETE, поддерживаемым с версии 23.3, и переносится запросом INSERT ... SELECT.
+	ClickHouse Dialect = "clickhouse"
+)
+
+// nullValue значение, которым Директ обозначает отсутствие данных.
+const nullValue = "--"
+
+func (d Dialect) valid() bool {
+	return d == Postgres || d == ClickHouse
+}
+
+// quote экранирует идентификатор. Имя вида schema.table экранируется по частям.
+func (d Dialect) quote(name string) string {
+	parts := strings.Split(name, ".")
+
+	for i, p := range parts {
+		if d == ClickHouse {
+			parts[i] = "`" + strings.ReplaceAll(p, "`", "``") + "`"
+		} else {
+			parts[i] = `"` + strings.ReplaceAll(p, `"`, `""`) + `"`
+		}
+	}
+
+	return strings.Join(parts, ".")
+}
+
+func (d Dialect) placeholder(i int) string {
+	if d == Postgres {
+		return "$" + strconv.Itoa(i)
+	}
+
+	return "?"
+}
+
+// columnType возвращает тип столбца для поля. Ключевые столбцы (логин и дата) не допускают NULL.
+func (d Dialect) columnType(kind statistics.FieldKind, key bool) string {
+	if d == Postgres {
+		t := map[statistics.FieldKind]string{
+			statistics.KindID:      "BIGINT",
+			statistics.KindInteger: "BIGINT",
+			statistics.KindMoney:   "BIGINT",
+			statistics.KindFloat:   "DOUBLE PRECISION",
+			statistics.KindDate:    "DATE",
+			statistics.KindEnum:    "TEXT",
+			statistics.KindString:  "TEXT",
+		}[kind]
+		if key {
+			t += " NOT NULL"
+		}
+
+		return t
+	}
+
+	t := map[statistics.FieldKind]string{
+		statistics.KindID:      "UInt64",
+		statistics.KindInteger: "Int64",
+		statistics.KindMoney:   "Int64",
+		statistics.KindFloat:   "Float64",
+		statistics.KindDate:    "Date",
+		statistics.KindEnum:    "String",
+		statistics.KindString:  "String",
+	}[kind]
+
+	if key {
+		return t
+	}
+
+	t = "Nullable(" + t + ")"
+	if kind == statistics.KindEnum {
+		t = "LowCardinality(" + t + ")"
+	}
+
+	return t
+}
+
+// value преобразует значение из отчета в значение для драйвера. «--» становится NULL.
+func (d Dialect) value(kind statistics.FieldKind, s string) (interface{}, error) {
+	if s == nullValue {
+		return nil, nil
+	}
+
+	switch kind {
+	case statistics.KindID:
+		if d == ClickHouse {
+			return strconv.ParseUint(s, 10, 64) //nolint:wrapcheck
+		}
+
+		return strconv.ParseInt(s, 10, 64) //nolint:wrapcheck
+	case statistics.KindInteger, statistics.KindMoney:
+		return strconv.ParseInt(s, 10, 64) //nolint:wrapcheck
+	case statistics.KindFloat:
+		return strconv.ParseFloat(s, 64) //nolint:wrapcheck
+	case statistics.KindDate:
+		return time.Parse(statistics.DateLayout, s) //nolint:wrapcheck
+	case statistics.KindEnum, statistics.KindString:
+	}
+
+	return s, nil
+}
+
+func (d Dialect) createTable(table string, cols []column) []string {
+	defs := make([]string, 0, len(cols))
+	for _, c := range cols {
+		defs = append(defs, fmt.Sprintf("%s %s", d.quote(c.name), d.columnType(c.kind, c.key)))
+	}
+
+	create := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n\t%s\n)", d.quote(table), strings.Join(defs, ",\n\t"))
+	login, date := d.quote(loginColumn), d.quote(dateColumn)
+
+	if d == ClickHouse {
+		return []string{fmt.Sprintf("%s\nENGINE = MergeTree\nPARTITION BY toYYYYMM(%s)\nORDER BY (%s, %s)", create, date, login, date)}
+	}
+
+	index := strings.ReplaceAll(table, ".", "_") + "_login_date_idx"
+
+	return []string{
+		create,
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (%s, %s)", d.quote(index), d.quote(table), login, date),
+	}
+}
+
+func (d Dialect) deleteRange(table string) string {
+	return fmt.Sprintf("DELETE FROM %s WHERE %s = %s AND %s BETWEEN %s AND %s",
+		d.quote(table), d.quote(loginColumn), d.placeholder(1), d.quote(dateColumn), d.placeholder(2), d.placeholder(3))
+}
+
+func (d Dialect) insert(table string, cols []column) string {
+	names := make([]string, 0, len(cols))
+	for _, c := range cols {
+		names = append(names, d.quote(c.name))
+	}
+
+	if d == Postgres {
+		return fmt.Sprintf("COPY %s (%s) FROM STDIN", d.quote(table), strings.Join(names, ", "))
+	}
+
+	return fmt.Sprintf("INSERT INTO %s (%s)", d.quote(table), strings.Join(names, ", "))
+}
+
+// createStage создает промежуточную таблицу stage со структурой и движком таблицы table.
+func (d Dialect) createStage(stage, table string) string {
+	return fmt.Sprintf("CREATE TABLE %s AS %s", d.quote(stage), d.quote(table))
+}
+
+// copyStage переносит строки промежуточной таблицы stage в таблицу table.
+func (d Dialect) copyStage(table, stage string, cols []column) string {
+	names := make([]string, 0, len(cols))
+	for _, c := range cols {
+		names = append(names, d.quote(c.name))
+	}
+
+	list := strings.Join(names, ", ")
+
+	return fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s", d.quote(table), list, list, d.quote(stage))
+}
+
+func (d Dialect) dropTable(table string) string {
+	return fmt.Sprintf("DROP TABLE IF EXISTS %s", d.quote(table))
+}
@@ -0,0 +1,74 @@
+package sqlsink
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDDL(t *testing.T) {
+	header := []string{"Date", "CampaignId", "AdNetworkType", "Clicks", "Cost", "Ctr", "Conversions_123_LSC"}
+
+	tests := []struct {
+		name    string
+		dialect Dialect
+		table   string
+		want    []string
+	}{
+		{
+			name:    "postgres",
+			dialect: Postgres,
+			table:   "direct.stats",
+			want: []string{
+				`CREATE TABLE IF NOT EXISTS "direct"."stats" (
+	"login" TEXT NOT NULL,
+	"date" DATE NOT NULL,
+	"campaign_id" BIGINT,
+	"ad_network_type" TEXT,
+	"clicks" BIGINT,
+	"cost" BIGINT,
+	"ctr" DOUBLE PRECISION,
+	"conversions_123_lsc" BIGINT
+)`,
+				`CREATE INDEX IF NOT EXISTS "direct_stats_login_date_idx" ON "direct"."stats" ("login", "date")`,
+			},
+		},
+		{
+			name:    "clickhouse",
+			dialect: ClickHouse,
+			table:   "stats",
+			want: []string{
+				"CREATE TABLE IF NOT EXISTS `stats` (\n" +
+					"\t`login` String,\n" +
+					"\t`date` Date,\n" +
+					"\t`campaign_id` Nullable(UInt64),\n" +
+					"\t`ad_network_type` LowCardinality(Nullable(String)),\n" +
+					"\t`clicks` Nullable(Int64),\n" +
+					"\t`cost` Nullable(Int64),\n" +
+					"\t`ctr` Nullable(Float64),\n" +
+					"\t`conversions_123_lsc` Nullable(Int64)\n" +
+					")\n" +
+					"ENGINE = MergeTree\n" +
+					"PARTITION BY toYYYYMM(`date`)\n" +
+					"ORDER BY (`login`, `date`)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := New(nil, tt.dialect, tt.table)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := s.DDL(header)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DDL =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
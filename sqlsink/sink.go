@@ -0,0 +1,371 @@
+package sqlsink
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	sdk "github.com/mg-realcom/yandex-direct-sdk"
+	"github.com/mg-realcom/yandex-direct-sdk/incremental"
+	"github.com/mg-realcom/yandex-direct-sdk/statistics"
+)
+
+const (
+	loginColumn = "login"
+	dateColumn  = "date"
+)
+
+var (
+	ErrNoDateField    = errors.New("report has no Date column")
+	ErrUnknownColumn  = errors.New("unknown report column")
+	ErrUnknownDialect = errors.New("unknown SQL dialect")
+	ErrHeaderMismatch = errors.New("report parts have different columns")
+	ErrRowLength      = errors.New("row length does not match header")
+	ErrNoTable        = errors.New("table name is empty")
+)
+
+// Sink загружает статистику в таблицу PostgreSQL или ClickHouse через database/sql. Драйвер базы
+// подключает вызывающий. Таблица содержит столбец login и столбцы отчета в snake_case.
+// Sink реализует incremental.Sink.
+type Sink struct {
+	db      *sql.DB
+	dialect Dialect
+	table   string
+}
+
+var _ incremental.Sink = (*Sink)(nil)
+
+// New создает Sink для таблицы table. Для Postgres нужен драйвер lib/pq: загрузка идет через
+// COPY ... FROM STDIN в подготовленном запросе, другие драйверы (например, pgx/stdlib) так не умеют.
+// Для ClickHouse нужен драйвер clickhouse-go.
+func New(db *sql.DB, dialect Dialect, table string) (*Sink, error) {
+	if !dialect.valid() {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownDialect, dialect)
+	}
+
+	if table == "" {
+		return nil, ErrNoTable
+	}
+
+	return &Sink{db: db, dialect: dialect, table: table}, nil
+}
+
+type column struct {
+	name  string // Имя столбца в таблице.
+	field string // Имя столбца в отчете.
+	kind  statistics.FieldKind
+	key   bool
+}
+
+// ColumnName возвращает имя столбца таблицы для столбца отчета: CampaignId — campaign_id,
+// Conversions_123_LSC — conversions_123_lsc.
+func ColumnName(field string) string {
+	var b strings.Builder
+
+	runes := []rune(field)
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])) {
+			b.WriteRune('_')
+		}
+
+		b.WriteRune(unicode.ToLower(r))
+	}
+
+	return b.String()
+}
+
+// columnKind определяет тип столбца отчета по справочнику полей, включая столбцы по целям
+// и столбцы длинного формата statistics.GoalUnpivot.
+func columnKind(name string) (statistics.FieldKind, bool) {
+	if info, ok := statistics.LookupField(statistics.Field(name)); ok {
+		return info.Kind, true
+	}
+
+	if goal, ok := statistics.ParseGoalColumn(name); ok {
+		info, _ := statistics.LookupField(goal.Field)
+
+		return info.Kind, true
+	}
+
+	switch name {
+	case statistics.ColumnGoalID:
+		return statistics.KindID, true
+	case statistics.ColumnAttributionModel:
+		return statistics.KindEnum, true
+	}
+
+	return 0, false
+}
+
+// columns строит столбцы таблицы по заголовку отчета. Первым идет login.
+func columns(header []string) ([]column, error) {
+	cols := []column{{name: loginColumn, kind: statistics.KindString, key: true}}
+	hasDate := false
+
+	for _, h := range header {
+		kind, ok := columnKind(h)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownColumn, h)
+		}
+
+		c := column{name: ColumnName(h), field: h, kind: kind}
+		if h == string(statistics.FieldDate) {
+			c.key = true
+			hasDate = true
+		}
+
+		cols = append(cols, c)
+	}
+
+	if !hasDate {
+		return nil, ErrNoDateField
+	}
+
+	return cols, nil
+}
+
+// DDL возвращает запросы создания таблицы для столбцов отчета header.
+func (s *Sink) DDL(header []string) ([]string, error) {
+	cols, err := columns(header)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.dialect.createTable(s.table, cols), nil
+}
+
+// CreateTable создает таблицу, если ее нет.
+func (s *Sink) CreateTable(ctx context.Context, header []string) error {
+	stmts, err := s.DDL(header)
+	if err != nil {
+		return err
+	}
+
+	for _, stmt := range stmts {
+		if _, err := s.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("create table %s: %w", s.table, err)
+		}
+	}
+
+	return nil
+}
+
+// ReplacePartition заменяет строки логина за дату партиции.
+func (s *Sink) ReplacePartition(ctx context.Context, p incremental.Partition, header []string, rows [][]string) error {
+	return s.ReplaceRange(ctx, p.Login, p.Date, p.Date, header, rows)
+}
+
+// ReplaceRange заменяет строки логина за период from–to включительно переданными строками.
+func (s *Sink) ReplaceRange(ctx context.Context, login, from, to string, header []string, rows [][]string) error {
+	i := 0
+
+	return s.replace(ctx, login, from, to, header, func() ([]string, error) {
+		if i == len(rows) {
+			return nil, io.EOF
+		}
+
+		i++
+
+		return rows[i-1], nil
+	})
+}
+
+// LoadFiles заменяет строки логина за период from–to строками из файлов частей отчета.
+// Файлы читаются потоково, заголовки частей должны совпадать.
+func (s *Sink) LoadFiles(ctx context.Context, login, from, to string, paths []string) error {
+	if len(paths) == 0 {
+		return s.deleteOnly(ctx, login, from, to)
+	}
+
+	readers := make([]*sdk.ReportReader, 0, len(paths))
+
+	defer func() {
+		for _, r := range readers {
+			r.Close()
+		}
+	}()
+
+	for _, path := range paths {
+		r, err := sdk.OpenReport(path)
+		if err != nil {
+			return err
+		}
+
+		readers = append(readers, r)
+
+		if strings.Join(r.Header(), "\t") != strings.Join(readers[0].Header(), "\t") {
+			return fmt.Errorf("%w: %s", ErrHeaderMismatch, path)
+		}
+	}
+
+	current := 0
+
+	return s.replace(ctx, login, from, to, readers[0].Header(), func() ([]string, error) {
+		for current < len(readers) {
+			row, err := readers[current].Next()
+			if errors.Is(err, io.EOF) {
+				current++
+
+				continue
+			}
+
+			return row, err
+		}
+
+		return nil, io.EOF
+	})
+}
+
+func (s *Sink) deleteOnly(ctx context.Context, login, from, to string) error {
+	if _, err := s.db.ExecContext(ctx, s.dialect.deleteRange(s.table), login, from, to); err != nil {
+		return fmt.Errorf("delete %s %s–%s: %w", login, from, to, err)
+	}
+
+	return nil
+}
+
+// replace удаляет период и вставляет строки. В PostgreSQL удаление и вставка выполняются в одной
+// транзакции. ClickHouse транзакций не поддерживает, поэтому строки сначала загружаются в промежуточную
+// таблицу: при ошибке чтения или вставки период в таблице не меняется.
+func (s *Sink) replace(ctx context.Context, login, from, to string, header []string, next func() ([]string, error)) error {
+	if len(header) == 0 {
+		return s.deleteOnly(ctx, login, from, to)
+	}
+
+	cols, err := columns(header)
+	if err != nil {
+		return err
+	}
+
+	if s.dialect == ClickHouse {
+		return s.replaceStaged(ctx, login, from, to, cols, next)
+	}
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, s.dialect.deleteRange(s.table), login, from, to); err != nil {
+			return fmt.Errorf("delete %s %s–%s: %w", login, from, to, err)
+		}
+
+		return s.insertRows(ctx, tx, s.table, login, cols, next)
+	})
+}
+
+// replaceStaged вставляет строки в промежуточную таблицу, затем удаляет период и переносит в него строки.
+// Между удалением и переносом период пуст, но ошибка загрузки отчета его уже не затрагивает.
+// Промежуточная таблица удаляется в любом случае.
+func (s *Sink) replaceStaged(ctx context.Context, login, from, to string, cols []column, next func() ([]string, error)) (err error) {
+	stage, err := stageName(s.table)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.db.ExecContext(ctx, s.dialect.createStage(stage, s.table)); err != nil {
+		return fmt.Errorf("create stage %s: %w", stage, err)
+	}
+
+	defer func() {
+		// Контекст может быть уже отменен, а таблицу нужно удалить.
+		if _, dropErr := s.db.ExecContext(context.Background(), s.dialect.dropTable(stage)); dropErr != nil && err == nil {
+			err = fmt.Errorf("drop stage %s: %w", stage, dropErr)
+		}
+	}()
+
+	if err := s.inTx(ctx, func(tx *sql.Tx) error {
+		return s.insertRows(ctx, tx, stage, login, cols, next)
+	}); err != nil {
+		return err
+	}
+
+	if err := s.deleteOnly(ctx, login, from, to); err != nil {
+		return err
+	}
+
+	if _, err := s.db.ExecContext(ctx, s.dialect.copyStage(s.table, stage, cols)); err != nil {
+		return fmt.Errorf("copy stage %s: %w", stage, err)
+	}
+
+	return nil
+}
+
+// stageName возвращает уникальное имя промежуточной таблицы рядом с table.
+func stageName(table string) (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("stage name: %w", err)
+	}
+
+	return table + "_stage_" + hex.EncodeToString(b), nil
+}
+
+// inTx выполняет fn в транзакции и откатывает ее при ошибке.
+func (s *Sink) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	return nil
+}
+
+// insertRows вставляет строки в table: в PostgreSQL через COPY, в ClickHouse пакетом.
+func (s *Sink) insertRows(ctx context.Context, tx *sql.Tx, table, login string, cols []column, next func() ([]string, error)) error {
+	stmt, err := tx.PrepareContext(ctx, s.dialect.insert(table, cols))
+	if err != nil {
+		return fmt.Errorf("prepare insert: %w", err)
+	}
+	defer stmt.Close()
+
+	fields := cols[1:]
+	args := make([]interface{}, len(cols))
+	args[0] = login
+
+	for n := 1; ; n++ {
+		row, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return fmt.Errorf("read row %d: %w", n, err)
+		}
+
+		if len(row) != len(fields) {
+			return fmt.Errorf("%w: row %d has %d values, header %d", ErrRowLength, n, len(row), len(fields))
+		}
+
+		for i, c := range fields {
+			if args[i+1], err = s.dialect.value(c.kind, row[i]); err != nil {
+				return fmt.Errorf("row %d, %s: %w", n, c.field, err)
+			}
+		}
+
+		if _, err := stmt.ExecContext(ctx, args...); err != nil {
+			return fmt.Errorf("insert row %d: %w", n, err)
+		}
+	}
+
+	if s.dialect == Postgres {
+		// Exec без аргументов завершает COPY.
+		if _, err := stmt.ExecContext(ctx); err != nil {
+			return fmt.Errorf("finish copy: %w", err)
+		}
+	}
+
+	return nil
+}
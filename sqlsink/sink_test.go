@@ -0,0 +1,251 @@
+package sqlsink
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var errExec = errors.New("exec failed")
+
+// recorder записывает вызовы драйвера в виде строк.
+type recorder struct {
+	calls  []string
+	failOn string // Exec запроса с этим префиксом (без суффикса имени stage) возвращает errExec.
+}
+
+func (r *recorder) Connect(context.Context) (driver.Conn, error) { return fakeConn{r}, nil }
+func (r *recorder) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct{ r *recorder }
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return fakeStmt{r: c.r, query: query}, nil
+}
+
+func (c fakeConn) Close() error { return nil }
+
+func (c fakeConn) Begin() (driver.Tx, error) {
+	c.r.calls = append(c.r.calls, "begin")
+
+	return fakeTx{c.r}, nil
+}
+
+type fakeTx struct{ r *recorder }
+
+func (t fakeTx) Commit() error {
+	t.r.calls = append(t.r.calls, "commit")
+
+	return nil
+}
+
+func (t fakeTx) Rollback() error {
+	t.r.calls = append(t.r.calls, "rollback")
+
+	return nil
+}
+
+type fakeStmt struct {
+	r     *recorder
+	query string
+}
+
+func (s fakeStmt) Close() error  { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.r.calls = append(s.r.calls, strings.TrimSpace(fmt.Sprint(s.query, " ", args)))
+
+	if s.r.failOn != "" && strings.HasPrefix(stagePattern.ReplaceAllString(s.query, "_stage"), s.r.failOn) {
+		return nil, errExec
+	}
+
+	return driver.RowsAffected(1), nil
+}
+
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	return nil, errors.New("not implemented")
+}
+
+var stagePattern = regexp.MustCompile(`_stage_[0-9a-f]{16}`)
+
+// stableCalls возвращает вызовы с постоянным именем промежуточной таблицы.
+func (r *recorder) stableCalls() []string {
+	var calls []string
+	for _, c := range r.calls {
+		calls = append(calls, stagePattern.ReplaceAllString(c, "_stage"))
+	}
+
+	return calls
+}
+
+func TestReplaceRange(t *testing.T) {
+	header := []string{"Date", "CampaignId", "Clicks"}
+	rows := [][]string{{"2024-01-01", "1", "5"}, {"2024-01-01", "2", "--"}}
+	date := "2024-01-01 00:00:00 +0000 UTC"
+
+	tests := []struct {
+		name    string
+		dialect Dialect
+		rows    [][]string
+		failOn  string
+		wantErr error
+		want    []string
+	}{
+		{
+			name:    "postgres",
+			dialect: Postgres,
+			rows:    rows,
+			want: []string{
+				"begin",
+				`DELETE FROM "stats" WHERE "login" = $1 AND "date" BETWEEN $2 AND $3 [acc 2024-01-01 2024-01-01]`,
+				`COPY "stats" ("login", "date", "campaign_id", "clicks") FROM STDIN [acc ` + date + ` 1 5]`,
+				`COPY "stats" ("login", "date", "campaign_id", "clicks") FROM STDIN [acc ` + date + ` 2 <nil>]`,
+				`COPY "stats" ("login", "date", "campaign_id", "clicks") FROM STDIN []`,
+				"commit",
+			},
+		},
+		{
+			name:    "postgres bad row",
+			dialect: Postgres,
+			rows:    [][]string{{"2024-01-01", "1"}},
+			wantErr: ErrRowLength,
+			want: []string{
+				"begin",
+				`DELETE FROM "stats" WHERE "login" = $1 AND "date" BETWEEN $2 AND $3 [acc 2024-01-01 2024-01-01]`,
+				"rollback",
+			},
+		},
+		{
+			name:    "clickhouse",
+			dialect: ClickHouse,
+			rows:    rows,
+			want: []string{
+				"CREATE TABLE `stats_stage` AS `stats` []",
+				"begin",
+				"INSERT INTO `stats_stage` (`login`, `date`, `campaign_id`, `clicks`) [acc " + date + " 1 5]",
+				"INSERT INTO `stats_stage` (`login`, `date`, `campaign_id`, `clicks`) [acc " + date + " 2 <nil>]",
+				"commit",
+				"DELETE FROM `stats` WHERE `login` = ? AND `date` BETWEEN ? AND ? [acc 2024-01-01 2024-01-01]",
+				"INSERT INTO `stats` (`login`, `date`, `campaign_id`, `clicks`) " +
+					"SELECT `login`, `date`, `campaign_id`, `clicks` FROM `stats_stage` []",
+				"DROP TABLE IF EXISTS `stats_stage` []",
+			},
+		},
+		{
+			name:    "clickhouse insert fails",
+			dialect: ClickHouse,
+			rows:    rows,
+			failOn:  "INSERT INTO `stats_stage`",
+			wantErr: errExec,
+			want: []string{
+				"CREATE TABLE `stats_stage` AS `stats` []",
+				"begin",
+				"INSERT INTO `stats_stage` (`login`, `date`, `campaign_id`, `clicks`) [acc " + date + " 1 5]",
+				"rollback",
+				"DROP TABLE IF EXISTS `stats_stage` []",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{failOn: tt.failOn}
+			db := sql.OpenDB(r)
+			defer db.Close()
+
+			s, err := New(db, tt.dialect, "stats")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = s.ReplaceRange(context.Background(), "acc", "2024-01-01", "2024-01-01", header, tt.rows)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+
+			if got := r.stableCalls(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("calls =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestLoadFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		return path
+	}
+
+	first := write("part_1.tsv", "Date\tClicks\n2024-01-01\t1\n2024-01-02\t2\n")
+	second := write("part_2.tsv", "Date\tClicks\n2024-01-03\t3\n")
+	other := write("other.tsv", "Date\tCost\n2024-01-03\t3\n")
+
+	tests := []struct {
+		name    string
+		paths   []string
+		wantErr error
+		want    []string
+	}{
+		{
+			name:  "parts",
+			paths: []string{first, second},
+			want: []string{
+				"begin",
+				`DELETE FROM "stats" WHERE "login" = $1 AND "date" BETWEEN $2 AND $3 [acc 2024-01-01 2024-01-03]`,
+				`COPY "stats" ("login", "date", "clicks") FROM STDIN [acc 2024-01-01 00:00:00 +0000 UTC 1]`,
+				`COPY "stats" ("login", "date", "clicks") FROM STDIN [acc 2024-01-02 00:00:00 +0000 UTC 2]`,
+				`COPY "stats" ("login", "date", "clicks") FROM STDIN [acc 2024-01-03 00:00:00 +0000 UTC 3]`,
+				`COPY "stats" ("login", "date", "clicks") FROM STDIN []`,
+				"commit",
+			},
+		},
+		{
+			name: "no files",
+			want: []string{
+				`DELETE FROM "stats" WHERE "login" = $1 AND "date" BETWEEN $2 AND $3 [acc 2024-01-01 2024-01-03]`,
+			},
+		},
+		{
+			name:    "header mismatch",
+			paths:   []string{first, other},
+			wantErr: ErrHeaderMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{}
+			db := sql.OpenDB(r)
+			defer db.Close()
+
+			s, err := New(db, Postgres, "stats")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = s.LoadFiles(context.Background(), "acc", "2024-01-01", "2024-01-03", tt.paths)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+
+			if got := r.stableCalls(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("calls =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package sqr
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mg-realcom/yandex-direct-sdk/statistics"
+)
+
+// DefaultMaxN наибольшая длина n-граммы по умолчанию.
+const DefaultMaxN = 3
+
+// Level уровень, на котором считаются n-граммы и предлагаются минус-фразы.
+type Level int
+
+const (
+	CampaignLevel Level = iota // Минус-фразы кампании.
+	AdGroupLevel               // Минус-фразы группы объявлений.
+)
+
+// Options параметры подсчета n-грамм.
+type Options struct {
+	Level     Level
+	MaxN      int             // Наибольшая длина n-граммы. По умолчанию DefaultMaxN.
+	StopWords map[string]bool // Стоп-слова. Nil означает DefaultStopWords.
+}
+
+// Scope кампания или группа объявлений. Для CampaignLevel AdGroupID равен 0.
+type Scope struct {
+	CampaignID int64
+	AdGroupID  int64
+}
+
+// NGramStat показатели запросов, содержащих n-грамму.
+type NGramStat struct {
+	Scope
+	NGram       string
+	N           int
+	Queries     int // Количество разных запросов с n-граммой.
+	Impressions int64
+	Clicks      int64
+	Cost        int64 // Расход в микроединицах валюты.
+	Conversions int64
+}
+
+// CPA возвращает стоимость конверсии в микроединицах или 0, если конверсий нет.
+func (s NGramStat) CPA() int64 {
+	if s.Conversions == 0 {
+		return 0
+	}
+
+	return s.Cost / s.Conversions
+}
+
+type statKey struct {
+	scope Scope
+	ngram string
+}
+
+// Aggregate считает показатели n-грамм длиной от 1 до MaxN. Показатели запроса учитываются в каждой
+// n-грамме один раз, даже если она встречается в запросе несколько раз. Результат упорядочен
+// по убыванию расхода.
+func Aggregate(rows []Row, opts Options) []NGramStat {
+	maxN := opts.MaxN
+	if maxN < 1 {
+		maxN = DefaultMaxN
+	}
+
+	stop := opts.StopWords
+	if stop == nil {
+		stop = DefaultStopWords
+	}
+
+	stats := map[statKey]*NGramStat{}
+	queries := map[statKey]map[string]bool{}
+
+	for _, row := range rows {
+		scope := Scope{CampaignID: row.CampaignID}
+		if opts.Level == AdGroupLevel {
+			scope.AdGroupID = row.AdGroupID
+		}
+
+		tokens := Normalize(row.Query, stop)
+		normalized := strings.Join(tokens, " ")
+
+		for n := 1; n <= maxN; n++ {
+			for _, g := range NGrams(tokens, n) {
+				key := statKey{scope: scope, ngram: g}
+
+				s, ok := stats[key]
+				if !ok {
+					s = &NGramStat{Scope: scope, NGram: g, N: n}
+					stats[key] = s
+					queries[key] = map[string]bool{}
+				}
+
+				if !queries[key][normalized] {
+					queries[key][normalized] = true
+					s.Queries++
+				}
+
+				s.Impressions += row.Impressions
+				s.Clicks += row.Clicks
+				s.Cost += row.Cost
+				s.Conversions += row.Conversions
+			}
+		}
+	}
+
+	out := make([]NGramStat, 0, len(stats))
+	for _, s := range stats {
+		out = append(out, *s)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Cost != out[j].Cost {
+			return out[i].Cost > out[j].Cost
+		}
+
+		return out[i].NGram < out[j].NGram
+	})
+
+	return out
+}
+
+// Thresholds условия, при которых n-грамма предлагается в минус-фразы.
+type Thresholds struct {
+	MinCost        float64 // Наименьший расход в единицах валюты.
+	MinClicks      int64   // Наименьшее количество кликов.
+	MaxConversions int64   // Наибольшее количество конверсий; обычно 0.
+	MaxCPA         float64 // Если больше 0, предлагаются и n-граммы с конверсиями, CPA которых выше, в единицах валюты.
+}
+
+// Candidate предлагаемая минус-фраза.
+type Candidate struct {
+	Scope
+	Keyword string // Минус-фраза без знака «-».
+	Reason  string
+	Stat    NGramStat
+}
+
+// Propose отбирает n-граммы, подходящие под пороги, и возвращает их как минус-фразы по кампаниям
+// или группам. Фраза не предлагается, если в той же кампании или группе уже предложена ее часть:
+// минус-слово «бесплатно» покрывает фразу «скачать бесплатно».
+func Propose(stats []NGramStat, t Thresholds) []Candidate {
+	minCost := statistics.ToMicros(t.MinCost)
+	maxCPA := statistics.ToMicros(t.MaxCPA)
+
+	matched := make([]Candidate, 0)
+
+	for _, s := range stats {
+		if s.Cost < minCost || s.Clicks < t.MinClicks {
+			continue
+		}
+
+		var reason string
+
+		switch {
+		case s.Conversions <= t.MaxConversions:
+			reason = "no conversions"
+			if s.Conversions > 0 {
+				reason = "few conversions"
+			}
+		case maxCPA > 0 && s.CPA() > maxCPA:
+			reason = "high CPA"
+		default:
+			continue
+		}
+
+		matched = append(matched, Candidate{Scope: s.Scope, Keyword: s.NGram, Reason: reason, Stat: s})
+	}
+
+	// Короткие фразы проверяются первыми, чтобы покрытые ими длинные фразы отбрасывались.
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].Stat.N < matched[j].Stat.N })
+
+	proposed := map[Scope][]string{}
+	out := make([]Candidate, 0, len(matched))
+
+	for _, c := range matched {
+		if covered(c.Keyword, proposed[c.Scope]) {
+			continue
+		}
+
+		proposed[c.Scope] = append(proposed[c.Scope], c.Keyword)
+		out = append(out, c)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Stat.Cost > out[j].Stat.Cost })
+
+	return out
+}
+
+// covered сообщает, что фраза содержит одну из уже предложенных фраз как последовательность слов.
+func covered(keyword string, proposed []string) bool {
+	padded := " " + keyword + " "
+
+	for _, p := range proposed {
+		if strings.Contains(padded, " "+p+" ") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,122 @@
+package sqr
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAggregate(t *testing.T) {
+	rows := []Row{
+		{CampaignID: 1, AdGroupID: 10, Query: "елка елка купить", Impressions: 10, Clicks: 2, Cost: 100, Conversions: 1},
+		{CampaignID: 1, AdGroupID: 11, Query: "Ёлка бесплатно", Impressions: 5, Clicks: 1, Cost: 50},
+		{CampaignID: 2, AdGroupID: 20, Query: "елка", Impressions: 1, Clicks: 1, Cost: 30},
+	}
+
+	tests := []struct {
+		name string
+		opts Options
+		want []NGramStat
+	}{
+		{
+			name: "campaign unigrams",
+			opts: Options{MaxN: 1},
+			want: []NGramStat{
+				{Scope: Scope{CampaignID: 1}, NGram: "елка", N: 1, Queries: 2, Impressions: 15, Clicks: 3, Cost: 150, Conversions: 1},
+				{Scope: Scope{CampaignID: 1}, NGram: "купить", N: 1, Queries: 1, Impressions: 10, Clicks: 2, Cost: 100, Conversions: 1},
+				{Scope: Scope{CampaignID: 1}, NGram: "бесплатно", N: 1, Queries: 1, Impressions: 5, Clicks: 1, Cost: 50},
+				{Scope: Scope{CampaignID: 2}, NGram: "елка", N: 1, Queries: 1, Impressions: 1, Clicks: 1, Cost: 30},
+			},
+		},
+		{
+			name: "ad group bigrams",
+			opts: Options{Level: AdGroupLevel, MaxN: 2},
+			want: []NGramStat{
+				{Scope: Scope{CampaignID: 1, AdGroupID: 10}, NGram: "елка", N: 1, Queries: 1, Impressions: 10, Clicks: 2, Cost: 100, Conversions: 1},
+				{Scope: Scope{CampaignID: 1, AdGroupID: 10}, NGram: "елка елка", N: 2, Queries: 1, Impressions: 10, Clicks: 2, Cost: 100, Conversions: 1},
+				{Scope: Scope{CampaignID: 1, AdGroupID: 10}, NGram: "елка купить", N: 2, Queries: 1, Impressions: 10, Clicks: 2, Cost: 100, Conversions: 1},
+				{Scope: Scope{CampaignID: 1, AdGroupID: 10}, NGram: "купить", N: 1, Queries: 1, Impressions: 10, Clicks: 2, Cost: 100, Conversions: 1},
+				{Scope: Scope{CampaignID: 1, AdGroupID: 11}, NGram: "бесплатно", N: 1, Queries: 1, Impressions: 5, Clicks: 1, Cost: 50},
+				{Scope: Scope{CampaignID: 1, AdGroupID: 11}, NGram: "елка", N: 1, Queries: 1, Impressions: 5, Clicks: 1, Cost: 50},
+				{Scope: Scope{CampaignID: 1, AdGroupID: 11}, NGram: "елка бесплатно", N: 2, Queries: 1, Impressions: 5, Clicks: 1, Cost: 50},
+				{Scope: Scope{CampaignID: 2, AdGroupID: 20}, NGram: "елка", N: 1, Queries: 1, Impressions: 1, Clicks: 1, Cost: 30},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Aggregate(rows, tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Aggregate =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPropose(t *testing.T) {
+	scope := Scope{CampaignID: 1}
+	other := Scope{CampaignID: 2}
+
+	stat := func(s Scope, ngram string, n int, clicks, cost, conversions int64) NGramStat {
+		return NGramStat{Scope: s, NGram: ngram, N: n, Clicks: clicks, Cost: cost, Conversions: conversions}
+	}
+
+	tests := []struct {
+		name  string
+		stats []NGramStat
+		t     Thresholds
+		want  []string
+	}{
+		{
+			name: "short phrase covers longer",
+			stats: []NGramStat{
+				stat(scope, "скачать бесплатно", 2, 5, 3_000_000, 0),
+				stat(scope, "бесплатно", 1, 5, 2_000_000, 0),
+				stat(scope, "бесплатно онлайн", 2, 5, 1_000_000, 0),
+			},
+			t:    Thresholds{MinCost: 1},
+			want: []string{"бесплатно"},
+		},
+		{
+			name: "covering is per scope",
+			stats: []NGramStat{
+				stat(scope, "бесплатно", 1, 5, 2_000_000, 0),
+				stat(other, "скачать бесплатно", 2, 5, 1_000_000, 0),
+			},
+			t:    Thresholds{MinCost: 1},
+			want: []string{"бесплатно", "скачать бесплатно"},
+		},
+		{
+			name: "word inside another word is not covered",
+			stats: []NGramStat{
+				stat(scope, "мини", 1, 5, 2_000_000, 0),
+				stat(scope, "мини-пекарня", 1, 5, 1_000_000, 0),
+			},
+			t:    Thresholds{MinCost: 1},
+			want: []string{"мини", "мини-пекарня"},
+		},
+		{
+			name: "thresholds",
+			stats: []NGramStat{
+				stat(scope, "дорого", 1, 5, 5_000_000, 1),
+				stat(scope, "дешево", 1, 1, 3_000_000, 0),
+				stat(scope, "мало", 1, 5, 500_000, 0),
+				stat(scope, "хорошо", 1, 5, 2_000_000, 2),
+			},
+			t:    Thresholds{MinCost: 1, MinClicks: 2, MaxCPA: 1.5},
+			want: []string{"дорого"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([]string, 0)
+			for _, c := range Propose(tt.stats, tt.t) {
+				got = append(got, c.Keyword)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Propose = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package sqr
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DefaultStopWords служебные слова, которые не участвуют в n-граммах.
+var DefaultStopWords = StopWords(
+	"а", "без", "в", "во", "да", "для", "до", "за", "и", "из", "или", "к", "как", "ко", "ли", "на", "над", "не",
+	"но", "о", "об", "от", "по", "под", "при", "про", "с", "со", "у", "что", "это",
+	"a", "an", "and", "at", "by", "for", "in", "of", "on", "or", "the", "to", "with",
+)
+
+// StopWords создает набор стоп-слов.
+func StopWords(words ...string) map[string]bool {
+	out := make(map[string]bool, len(words))
+	for _, w := range words {
+		out[strings.ToLower(w)] = true
+	}
+
+	return out
+}
+
+// Normalize разбивает запрос на слова без лемматизации: приводит к нижнему регистру, заменяет «ё» на «е»,
+// отбрасывает операторы и знаки препинания и удаляет стоп-слова. Дефис и точка внутри слова сохраняются.
+func Normalize(query string, stop map[string]bool) []string {
+	query = strings.ReplaceAll(strings.ToLower(query), "ё", "е")
+
+	fields := strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '.'
+	})
+
+	tokens := make([]string, 0, len(fields))
+
+	for _, f := range fields {
+		f = strings.Trim(f, "-.")
+		if f == "" || stop[f] {
+			continue
+		}
+
+		tokens = append(tokens, f)
+	}
+
+	return tokens
+}
+
+// NGrams возвращает n-граммы из подряд идущих слов, без повторов.
+func NGrams(tokens []string, n int) []string {
+	if n < 1 || len(tokens) < n {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(tokens)-n+1)
+	out := make([]string, 0, len(tokens)-n+1)
+
+	for i := 0; i+n <= len(tokens); i++ {
+		g := strings.Join(tokens[i:i+n], " ")
+		if !seen[g] {
+			seen[g] = true
+			out = append(out, g)
+		}
+	}
+
+	return out
+}
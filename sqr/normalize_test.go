@@ -0,0 +1,59 @@
+package sqr
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		stop  map[string]bool
+		want  []string
+	}{
+		{name: "case and yo", query: "Купить Ёлку", want: []string{"купить", "елку"}},
+		{name: "operators", query: `"+купить !ёлку" -бесплатно [москва]`, want: []string{"купить", "елку", "бесплатно", "москва"}},
+		{name: "stop words", query: "ёлка для дома и дачи", want: []string{"елка", "дома", "дачи"}},
+		{name: "custom stop words", query: "ёлка для дома", stop: StopWords("Дома"), want: []string{"елка", "для"}},
+		{name: "inner hyphen and dot", query: "wi-fi роутер 2.4 ггц -", want: []string{"wi-fi", "роутер", "2.4", "ггц"}},
+		{name: "empty", query: " , ", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stop := tt.stop
+			if stop == nil {
+				stop = DefaultStopWords
+			}
+
+			if got := Normalize(tt.query, stop); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNGrams(t *testing.T) {
+	tokens := []string{"купить", "елку", "купить", "елку"}
+
+	tests := []struct {
+		name string
+		n    int
+		want []string
+	}{
+		{name: "unigrams without repeats", n: 1, want: []string{"купить", "елку"}},
+		{name: "bigrams without repeats", n: 2, want: []string{"купить елку", "елку купить"}},
+		{name: "whole query", n: 4, want: []string{"купить елку купить елку"}},
+		{name: "longer than query", n: 5, want: nil},
+		{name: "zero", n: 0, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NGrams(tokens, tt.n); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NGrams(%d) = %q, want %q", tt.n, got, tt.want)
+			}
+		})
+	}
+}
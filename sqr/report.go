@@ -0,0 +1,133 @@
+package sqr
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	sdk "github.com/mg-realcom/yandex-direct-sdk"
+	"github.com/mg-realcom/yandex-direct-sdk/statistics"
+)
+
+// reportPrefix префикс имени отчета; полное имя строит Client.ReportName в Fetch.
+const reportPrefix = "sqr"
+
+// Fields поля отчета, необходимые для анализа запросов.
+var Fields = []statistics.Field{
+	statistics.FieldCampaignID,
+	statistics.FieldAdGroupID,
+	statistics.FieldQuery,
+	statistics.FieldImpressions,
+	statistics.FieldClicks,
+	statistics.FieldCost,
+	statistics.FieldConversions,
+}
+
+// Row строка отчета по поисковым запросам.
+type Row struct {
+	CampaignID  int64
+	AdGroupID   int64
+	Query       string
+	Impressions int64
+	Clicks      int64
+	Cost        int64 // Расход в микроединицах валюты.
+	Conversions int64
+}
+
+// Report возвращает построитель отчета по поисковым запросам за период. С clickedOnly в отчет попадают
+// только запросы с кликами: без кликов нет расхода, но такие запросы нужны для анализа показов.
+// Цели и модели атрибуции можно задать через Goals и Attribution, но они не должны разворачивать
+// Conversions по целям.
+func Report(from, to time.Time, clickedOnly bool) *statistics.ReportBuilder {
+	b := statistics.NewReport(statistics.SearchQueryPerformanceReport).
+		Name(reportPrefix).
+		Dates(from, to).
+		Fields(Fields...)
+
+	if clickedOnly {
+		b = b.Where(statistics.FieldClicks, statistics.GreaterThan, "0")
+	}
+
+	return b
+}
+
+// Fetch получает отчет по поисковым запросам и разбирает строки. ReportName определения используется
+// как префикс имени, само имя строит Client.ReportName. Файлы отчета сохраняются в dir и удаляются после чтения.
+func Fetch(ctx context.Context, client *sdk.Client, dir string, def statistics.ReportDefinition) ([]Row, error) {
+	prefix := def.ReportName
+	if prefix == "" {
+		prefix = reportPrefix
+	}
+
+	def.ReportName = client.ReportName(prefix, def)
+
+	files, err := client.GetFiles(ctx, dir, def)
+	if err != nil {
+		return nil, fmt.Errorf("GetFiles: %w", err)
+	}
+
+	defer func() {
+		for _, f := range files {
+			_ = os.Remove(f)
+		}
+	}()
+
+	var rows []Row
+
+	err = sdk.ReadReports(files, func(header, values []string) error {
+		row, err := parseRow(header, values)
+		if err != nil {
+			return err
+		}
+
+		rows = append(rows, row)
+
+		return nil
+	})
+
+	return rows, err
+}
+
+func parseRow(header, values []string) (Row, error) {
+	var (
+		row Row
+		err error
+	)
+
+	get := func(f statistics.Field) string {
+		i := sdk.ColumnIndex(header, string(f))
+		if i < 0 || i >= len(values) {
+			return ""
+		}
+
+		return values[i]
+	}
+
+	num := func(f statistics.Field) int64 {
+		s := get(f)
+		if err != nil || s == "" || s == "--" {
+			return 0
+		}
+
+		var n int64
+
+		n, err = strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			err = fmt.Errorf("%s: %w", f, err)
+		}
+
+		return n
+	}
+
+	row.Query = get(statistics.FieldQuery)
+	row.CampaignID = num(statistics.FieldCampaignID)
+	row.AdGroupID = num(statistics.FieldAdGroupID)
+	row.Impressions = num(statistics.FieldImpressions)
+	row.Clicks = num(statistics.FieldClicks)
+	row.Cost = num(statistics.FieldCost)
+	row.Conversions = num(statistics.FieldConversions)
+
+	return row, err
+}
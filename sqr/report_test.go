@@ -0,0 +1,40 @@
+package sqr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mg-realcom/yandex-direct-sdk/statistics"
+)
+
+func TestReportClickedOnly(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 6)
+
+	tests := []struct {
+		name        string
+		clickedOnly bool
+		filters     int
+	}{
+		{name: "clicked only", clickedOnly: true, filters: 1},
+		{name: "all queries", clickedOnly: false, filters: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			def, err := Report(from, to, tt.clickedOnly).Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			filters := def.Selection.Filter
+			if len(filters) != tt.filters {
+				t.Fatalf("filters = %+v, want %d", filters, tt.filters)
+			}
+
+			if tt.filters > 0 && (filters[0].Fields != string(statistics.FieldClicks) || filters[0].Operator != statistics.GreaterThan) {
+				t.Errorf("filter = %+v, want Clicks > 0", filters[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,377 @@
+package statistics
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mg-realcom/yandex-direct-sdk/common"
+)
+
+const (
+	// DateLayout формат дат в SelectionCriteria.
+	DateLayout = "2006-01-02"
+	// DefaultPageLimit ограничение строк в одной части отчета.
+	DefaultPageLimit = 50_000
+)
+
+// ErrInvalidReport ошибка проверки определения отчета.
+var ErrInvalidReport = errors.New("invalid report definition")
+
+// Moscow часовой пояс, в котором Директ считает даты статистики.
+var Moscow = time.FixedZone("MSK", 3*60*60)
+
+// ReportBuilder собирает ReportDefinition и проверяет его по справочнику полей.
+// Ошибки накапливаются и возвращаются из Build.
+type ReportBuilder struct {
+	def  ReportDefinition
+	errs []string
+}
+
+// NewReport создает построитель отчета типа t в формате TSV без НДС.
+func NewReport(t ReportType) *ReportBuilder {
+	return &ReportBuilder{
+		def: ReportDefinition{
+			Selection:     &SelectionCriteria{},
+			Page:          &common.Page{Limit: DefaultPageLimit},
+			ReportType:    t,
+			DateRangeType: DateRangeCustomDate,
+			Format:        common.FormatTSV,
+			IncludeVAT:    common.NO,
+		},
+	}
+}
+
+func (b *ReportBuilder) fail(format string, args ...interface{}) {
+	b.errs = append(b.errs, fmt.Sprintf(format, args...))
+}
+
+// Name задает имя отчета. Если имя не задано, оно формируется из типа отчета и периода.
+func (b *ReportBuilder) Name(name string) *ReportBuilder {
+	b.def.ReportName = name
+
+	return b
+}
+
+// Fields добавляет поля отчета.
+func (b *ReportBuilder) Fields(fields ...Field) *ReportBuilder {
+	for _, f := range fields {
+		b.def.FieldNames = append(b.def.FieldNames, string(f))
+	}
+
+	return b
+}
+
+// Where добавляет условие фильтрации.
+func (b *ReportBuilder) Where(field Field, op FilterOperator, values ...string) *ReportBuilder {
+	b.def.Selection.Filter = append(b.def.Selection.Filter, Filter{Fields: string(field), Operator: op, Values: values})
+
+	return b
+}
+
+// Filter добавляет условие, созданное типизированным конструктором, например Filter(IDIn(FieldCampaignID, 1, 2)).
+// Ошибка конструктора возвращается из Build.
+func (b *ReportBuilder) Filter(f Filter, err error) *ReportBuilder {
+	if err != nil {
+		b.fail("%v", err)
+
+		return b
+	}
+
+	b.def.Selection.Filter = append(b.def.Selection.Filter, f)
+
+	return b
+}
+
+// Goals задает идентификаторы целей Метрики для показателей конверсий. Директ принимает не больше
+// MaxGoals целей; определения с большим количеством целей разбиваются SplitGoals.
+func (b *ReportBuilder) Goals(goals ...string) *ReportBuilder {
+	if b.def.Goals == nil {
+		b.def.Goals = &[]string{}
+	}
+
+	*b.def.Goals = append(*b.def.Goals, goals...)
+
+	return b
+}
+
+// Attribution задает модели атрибуции для показателей конверсий.
+func (b *ReportBuilder) Attribution(models ...AttributionModel) *ReportBuilder {
+	if b.def.AttributionModels == nil {
+		b.def.AttributionModels = &[]AttributionModel{}
+	}
+
+	*b.def.AttributionModels = append(*b.def.AttributionModels, models...)
+
+	return b
+}
+
+// OrderBy добавляет сортировку по полю.
+func (b *ReportBuilder) OrderBy(field Field, order OrderBySortOrder) *ReportBuilder {
+	if b.def.OrderBy == nil {
+		b.def.OrderBy = &[]OrderBy{}
+	}
+
+	*b.def.OrderBy = append(*b.def.OrderBy, OrderBy{Field: string(field), SortOrder: order})
+
+	return b
+}
+
+// DateRange задает произвольный период в формате YYYY-MM-DD.
+func (b *ReportBuilder) DateRange(from, to string) *ReportBuilder {
+	b.def.DateRangeType = DateRangeCustomDate
+	b.def.Selection.DateFrom = from
+	b.def.Selection.DateTo = to
+
+	return b
+}
+
+// Dates задает произвольный период.
+func (b *ReportBuilder) Dates(from, to time.Time) *ReportBuilder {
+	return b.DateRange(from.Format(DateLayout), to.Format(DateLayout))
+}
+
+// Period задает предопределенный период, например DateRangeLastWeek или DateRangeAuto.
+func (b *ReportBuilder) Period(t DateRangeType) *ReportBuilder {
+	b.def.DateRangeType = t
+	b.def.Selection.DateFrom = ""
+	b.def.Selection.DateTo = ""
+
+	return b
+}
+
+// Page задает ограничение строк в одной части отчета.
+func (b *ReportBuilder) Page(limit int) *ReportBuilder {
+	if limit <= 0 {
+		b.fail("page limit must be positive, got %d", limit)
+
+		return b
+	}
+
+	b.def.Page = &common.Page{Limit: limit}
+
+	return b
+}
+
+// Format задает формат отчета.
+func (b *ReportBuilder) Format(f common.Format) *ReportBuilder {
+	b.def.Format = f
+
+	return b
+}
+
+// IncludeVAT задает учет НДС в денежных показателях.
+func (b *ReportBuilder) IncludeVAT(v bool) *ReportBuilder {
+	b.def.IncludeVAT = yesNo(v)
+
+	return b
+}
+
+// IncludeDiscount задает учет скидки в денежных показателях.
+func (b *ReportBuilder) IncludeDiscount(v bool) *ReportBuilder {
+	d := yesNo(v)
+	b.def.IncludeDiscount = &d
+
+	return b
+}
+
+func yesNo(v bool) common.YesNo {
+	if v {
+		return common.YES
+	}
+
+	return common.NO
+}
+
+// Build проверяет определение и возвращает его копию.
+func (b *ReportBuilder) Build() (ReportDefinition, error) {
+	errs := append([]string(nil), b.errs...)
+	errs = append(errs, Validate(b.def)...)
+
+	if len(errs) > 0 {
+		return ReportDefinition{}, fmt.Errorf("%w: %s", ErrInvalidReport, strings.Join(errs, "; "))
+	}
+
+	def := b.def.clone()
+	if def.ReportName == "" {
+		def.ReportName = defaultName(def)
+	}
+
+	return def, nil
+}
+
+// MustBuild как Build, но паникует при ошибке. Предназначен для определений, заданных в коде.
+func (b *ReportBuilder) MustBuild() ReportDefinition {
+	def, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	return def
+}
+
+func defaultName(def ReportDefinition) string {
+	name := strings.ToLower(string(def.ReportType))
+	if def.DateRangeType != DateRangeCustomDate {
+		return name + "_" + strings.ToLower(string(def.DateRangeType))
+	}
+
+	return fmt.Sprintf("%s_%s_%s", name, def.Selection.DateFrom, def.Selection.DateTo)
+}
+
+// clone копирует определение вместе со срезами и указателями, чтобы построитель можно было переиспользовать.
+func (d ReportDefinition) clone() ReportDefinition {
+	out := d
+	out.FieldNames = append([]string(nil), d.FieldNames...)
+
+	if d.Selection != nil {
+		sel := *d.Selection
+		sel.Filter = make([]Filter, 0, len(d.Selection.Filter))
+
+		for _, f := range d.Selection.Filter {
+			f.Values = append([]string(nil), f.Values...)
+			sel.Filter = append(sel.Filter, f)
+		}
+
+		if len(sel.Filter) == 0 {
+			sel.Filter = nil
+		}
+
+		out.Selection = &sel
+	}
+
+	if d.Goals != nil {
+		goals := append([]string(nil), *d.Goals...)
+		out.Goals = &goals
+	}
+
+	if d.AttributionModels != nil {
+		models := append([]AttributionModel(nil), *d.AttributionModels...)
+		out.AttributionModels = &models
+	}
+
+	if d.OrderBy != nil {
+		order := append([]OrderBy(nil), *d.OrderBy...)
+		out.OrderBy = &order
+	}
+
+	if d.Page != nil {
+		page := *d.Page
+		out.Page = &page
+	}
+
+	if d.IncludeDiscount != nil {
+		v := *d.IncludeDiscount
+		out.IncludeDiscount = &v
+	}
+
+	return out
+}
+
+// Validate проверяет определение отчета по справочнику полей и возвращает список нарушений.
+func Validate(def ReportDefinition) []string {
+	var errs []string
+
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Sprintf(format, args...))
+	}
+
+	if !knownReportType(def.ReportType) {
+		fail("unknown report type %q", def.ReportType)
+	}
+
+	if len(def.FieldNames) == 0 {
+		fail("no fields")
+	}
+
+	selected := make(map[string]bool, len(def.FieldNames))
+
+	for _, name := range def.FieldNames {
+		if selected[name] {
+			fail("duplicate field %s", name)
+		}
+
+		selected[name] = true
+
+		info, ok := catalogue[Field(name)]
+
+		switch {
+		case !ok:
+			fail("unknown field %s", name)
+		case !info.SupportedBy(def.ReportType):
+			fail("field %s is not available in %s", name, def.ReportType)
+		}
+	}
+
+	if def.Selection != nil {
+		for _, f := range def.Selection.Filter {
+			if err := f.Validate(); err != nil {
+				fail("%v", err)
+
+				continue
+			}
+
+			if info := catalogue[Field(f.Fields)]; !info.SupportedBy(def.ReportType) {
+				fail("filter field %s is not available in %s", f.Fields, def.ReportType)
+			}
+		}
+	}
+
+	if def.OrderBy != nil {
+		for _, o := range *def.OrderBy {
+			if !selected[o.Field] {
+				fail("order field %s is not among report fields", o.Field)
+			}
+		}
+	}
+
+	if def.Goals != nil && len(*def.Goals) == 0 {
+		fail("empty goals")
+	}
+
+	errs = append(errs, validateDates(def)...)
+
+	return errs
+}
+
+func validateDates(def ReportDefinition) []string {
+	var from, to string
+	if def.Selection != nil {
+		from, to = def.Selection.DateFrom, def.Selection.DateTo
+	}
+
+	if def.DateRangeType != DateRangeCustomDate {
+		if from != "" || to != "" {
+			return []string{fmt.Sprintf("dates are allowed only with %s", DateRangeCustomDate)}
+		}
+
+		return nil
+	}
+
+	start, err := time.Parse(DateLayout, from)
+	if err != nil {
+		return []string{fmt.Sprintf("invalid DateFrom %q", from)}
+	}
+
+	end, err := time.Parse(DateLayout, to)
+	if err != nil {
+		return []string{fmt.Sprintf("invalid DateTo %q", to)}
+	}
+
+	if end.Before(start) {
+		return []string{fmt.Sprintf("DateTo %s is before DateFrom %s", to, from)}
+	}
+
+	return nil
+}
+
+func knownReportType(t ReportType) bool {
+	switch t {
+	case AccountPerformanceReport, CampaignPerformanceReport, AdgroupPerformanceReport, AdPerformanceReport,
+		CriteriaPerformanceReport, CustomReport, ReachAndFrequencyPerformanceReport, SearchQueryPerformanceReport:
+		return true
+	}
+
+	return false
+}
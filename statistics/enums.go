@@ -0,0 +1,200 @@
+package statistics
+
+// Device тип устройства, на котором показано объявление.
+type Device string
+
+const (
+	DeviceDesktop Device = "DESKTOP"  // Компьютер.
+	DeviceMobile  Device = "MOBILE"   // Смартфон.
+	DeviceTablet  Device = "TABLET"   // Планшет.
+	DeviceSmartTV Device = "SMART_TV" // Смарт-ТВ.
+)
+
+// AdNetworkType тип площадки: поиск или Рекламная сеть Яндекса.
+type AdNetworkType string
+
+const (
+	AdNetworkSearch    AdNetworkType = "SEARCH"     // Поиск.
+	AdNetworkAdNetwork AdNetworkType = "AD_NETWORK" // Рекламная сеть Яндекса.
+)
+
+// Gender пол пользователя.
+type Gender string
+
+const (
+	GenderMale    Gender = "GENDER_MALE"   // Мужчины.
+	GenderFemale  Gender = "GENDER_FEMALE" // Женщины.
+	GenderUnknown Gender = "UNKNOWN"       // Пол не определен.
+)
+
+// Age возрастная группа пользователя.
+type Age string
+
+const (
+	Age0To17   Age = "AGE_0_17"  // Младше 18 лет.
+	Age18To24  Age = "AGE_18_24" // От 18 до 24 лет.
+	Age25To34  Age = "AGE_25_34" // От 25 до 34 лет.
+	Age35To44  Age = "AGE_35_44" // От 35 до 44 лет.
+	Age45To54  Age = "AGE_45_54" // От 45 до 54 лет.
+	Age45      Age = "AGE_45"    // Старше 45 лет, для статистики до 2019 года.
+	Age55      Age = "AGE_55"    // Старше 55 лет.
+	AgeUnknown Age = "UNKNOWN"   // Возраст не определен.
+)
+
+// CampaignType тип кампании.
+type CampaignType string
+
+const (
+	CampaignTypeText         CampaignType = "TEXT_CAMPAIGN"              // Текстово-графические объявления.
+	CampaignTypeMobileApp    CampaignType = "MOBILE_APP_CAMPAIGN"        // Реклама мобильных приложений.
+	CampaignTypeDynamicText  CampaignType = "DYNAMIC_TEXT_CAMPAIGN"      // Динамические объявления.
+	CampaignTypeSmart        CampaignType = "SMART_CAMPAIGN"             // Смарт-баннеры.
+	CampaignTypeCpmBanner    CampaignType = "CPM_BANNER_CAMPAIGN"        // Медийная кампания.
+	CampaignTypeCpmDeals     CampaignType = "CPM_DEALS_CAMPAIGN"         // Медийная кампания со сделками.
+	CampaignTypeCpmFrontpage CampaignType = "CPM_FRONTPAGE_CAMPAIGN"     // Медийная кампания на Главной.
+	CampaignTypeCpmPrice     CampaignType = "CPM_PRICE"                  // Кампания с фиксированным CPM.
+	CampaignTypeUnified      CampaignType = "UNIFIED_CAMPAIGN"           // Единая перфоманс-кампания.
+	CampaignTypeMcBanner     CampaignType = "MCBANNER_CAMPAIGN"          // Баннер на поиске.
+	CampaignTypeContentPromo CampaignType = "CONTENT_PROMOTION_CAMPAIGN" // Продвижение контента.
+)
+
+// MatchType тип соответствия поискового запроса ключевой фразе.
+type MatchType string
+
+const (
+	MatchTypeKeyword        MatchType = "KEYWORD"         // Запрос совпал с ключевой фразой.
+	MatchTypeRelatedKeyword MatchType = "RELATED_KEYWORD" // Показ по дополнительной релевантной фразе.
+	MatchTypeSynonym        MatchType = "SYNONYM"         // Показ по синониму ключевой фразы.
+	MatchTypeNone           MatchType = "NONE"            // Показ не по ключевой фразе.
+)
+
+// MobilePlatform операционная система мобильного устройства.
+type MobilePlatform string
+
+const (
+	MobilePlatformAndroid MobilePlatform = "ANDROID" // Android.
+	MobilePlatformIOS     MobilePlatform = "IOS"     // iOS.
+	MobilePlatformOther   MobilePlatform = "OTHER"   // Другая платформа.
+	MobilePlatformUnknown MobilePlatform = "UNKNOWN" // Платформа не определена.
+)
+
+// CarrierType тип подключения к интернету.
+type CarrierType string
+
+const (
+	CarrierCellular   CarrierType = "CELLULAR"   // Мобильная связь.
+	CarrierStationary CarrierType = "STATIONARY" // Wi-Fi или проводной интернет.
+	CarrierUnknown    CarrierType = "UNKNOWN"    // Тип связи не определен.
+)
+
+// IncomeGrade уровень платежеспособности пользователя.
+type IncomeGrade string
+
+const (
+	IncomeVeryHigh     IncomeGrade = "VERY_HIGH"     // Премиум.
+	IncomeHigh         IncomeGrade = "HIGH"          // Высокий.
+	IncomeAboveAverage IncomeGrade = "ABOVE_AVERAGE" // Выше среднего.
+	IncomeOther        IncomeGrade = "OTHER"         // Средний и ниже или не определен.
+)
+
+// TargetingCategory категория таргетинга автотаргетинга.
+type TargetingCategory string
+
+const (
+	TargetingExact       TargetingCategory = "EXACT"       // Целевые запросы.
+	TargetingAlternative TargetingCategory = "ALTERNATIVE" // Альтернативные запросы.
+	TargetingCompetitor  TargetingCategory = "COMPETITOR"  // Запросы с упоминанием конкурентов.
+	TargetingBroader     TargetingCategory = "BROADER"     // Широкие запросы.
+	TargetingAccessory   TargetingCategory = "ACCESSORY"   // Сопутствующие запросы.
+)
+
+// Slot блок показа объявления на поиске.
+type Slot string
+
+const (
+	SlotPremiumBlock   Slot = "PREMIUMBLOCK"    // Спецразмещение.
+	SlotAlone          Slot = "ALONE"           // Эксклюзивное размещение.
+	SlotSuggest        Slot = "SUGGEST"         // Поисковые подсказки.
+	SlotProductGallery Slot = "PRODUCT_GALLERY" // Товарная галерея.
+	SlotOther          Slot = "OTHER"           // Остальные блоки и сети.
+)
+
+// ClickType место объявления, по которому кликнул пользователь.
+type ClickType string
+
+const (
+	ClickTitle          ClickType = "TITLE"            // Заголовок.
+	ClickSitelink1      ClickType = "SITELINK1"        // Первая быстрая ссылка.
+	ClickSitelink2      ClickType = "SITELINK2"        // Вторая быстрая ссылка.
+	ClickSitelink3      ClickType = "SITELINK3"        // Третья быстрая ссылка.
+	ClickSitelink4      ClickType = "SITELINK4"        // Четвертая быстрая ссылка.
+	ClickSitelink5      ClickType = "SITELINK5"        // Пятая быстрая ссылка.
+	ClickSitelink6      ClickType = "SITELINK6"        // Шестая быстрая ссылка.
+	ClickSitelink7      ClickType = "SITELINK7"        // Седьмая быстрая ссылка.
+	ClickSitelink8      ClickType = "SITELINK8"        // Восьмая быстрая ссылка.
+	ClickVCard          ClickType = "VCARD"            // Виртуальная визитка.
+	ClickPhone          ClickType = "PHONE"            // Номер телефона.
+	ClickDisplayURLPath ClickType = "DISPLAY_URL_PATH" // Отображаемая ссылка.
+	ClickMobileAppIcon  ClickType = "MOBILE_APP_ICON"  // Значок мобильного приложения.
+	ClickButton         ClickType = "BUTTON"           // Кнопка.
+	ClickUnknown        ClickType = "UNKNOWN"          // Место клика не определено.
+)
+
+// AdFormat формат показанного объявления.
+type AdFormat string
+
+const (
+	AdFormatText          AdFormat = "TEXT"           // Текстовое объявление.
+	AdFormatImage         AdFormat = "IMAGE"          // Графическое объявление.
+	AdFormatVideo         AdFormat = "VIDEO"          // Видеообъявление.
+	AdFormatSmartSingle   AdFormat = "SMART_SINGLE"   // Смарт-баннер с одним товаром.
+	AdFormatSmartMultiple AdFormat = "SMART_MULTIPLE" // Смарт-баннер с несколькими товарами.
+	AdFormatSmartTile     AdFormat = "SMART_TILE"     // Смарт-плитка.
+	AdFormatSmartVideo    AdFormat = "SMART_VIDEO"    // Смарт-видео.
+)
+
+// CriterionType тип условия показа.
+type CriterionType string
+
+const (
+	CriterionKeyword             CriterionType = "KEYWORD"                // Ключевая фраза.
+	CriterionAutotargeting       CriterionType = "AUTOTARGETING"          // Автотаргетинг.
+	CriterionAudienceTarget      CriterionType = "AUDIENCE_TARGET"        // Условие нацеливания на аудиторию.
+	CriterionDynamicTextAdTarget CriterionType = "DYNAMIC_TEXT_AD_TARGET" // Условие нацеливания динамических объявлений.
+	CriterionSmartBannerFilter   CriterionType = "SMART_BANNER_FILTER"    // Фильтр смарт-баннеров.
+	CriterionWebpageFilter       CriterionType = "WEBPAGE_FILTER"         // Фильтр страниц сайта.
+	CriterionFeedFilter          CriterionType = "FEED_FILTER"            // Фильтр фида.
+	CriterionOfferRetargeting    CriterionType = "OFFER_RETARGETING"      // Ретаргетинг на товарные предложения.
+)
+
+// enumValues допустимые значения полей-перечислений. Поля без записи значения не проверяются.
+var enumValues = map[Field][]string{
+	FieldDevice:            {string(DeviceDesktop), string(DeviceMobile), string(DeviceTablet), string(DeviceSmartTV)},
+	FieldAdNetworkType:     {string(AdNetworkSearch), string(AdNetworkAdNetwork)},
+	FieldGender:            {string(GenderMale), string(GenderFemale), string(GenderUnknown)},
+	FieldAge:               {string(Age0To17), string(Age18To24), string(Age25To34), string(Age35To44), string(Age45To54), string(Age45), string(Age55), string(AgeUnknown)},
+	FieldMatchType:         {string(MatchTypeKeyword), string(MatchTypeRelatedKeyword), string(MatchTypeSynonym), string(MatchTypeNone)},
+	FieldMobilePlatform:    {string(MobilePlatformAndroid), string(MobilePlatformIOS), string(MobilePlatformOther), string(MobilePlatformUnknown)},
+	FieldCarrierType:       {string(CarrierCellular), string(CarrierStationary), string(CarrierUnknown)},
+	FieldIncomeGrade:       {string(IncomeVeryHigh), string(IncomeHigh), string(IncomeAboveAverage), string(IncomeOther)},
+	FieldTargetingCategory: {string(TargetingExact), string(TargetingAlternative), string(TargetingCompetitor), string(TargetingBroader), string(TargetingAccessory)},
+	FieldSlot:              {string(SlotPremiumBlock), string(SlotAlone), string(SlotSuggest), string(SlotProductGallery), string(SlotOther)},
+	FieldAdFormat: {
+		string(AdFormatText), string(AdFormatImage), string(AdFormatVideo),
+		string(AdFormatSmartSingle), string(AdFormatSmartMultiple), string(AdFormatSmartTile), string(AdFormatSmartVideo),
+	},
+	FieldClickType: {
+		string(ClickTitle), string(ClickSitelink1), string(ClickSitelink2), string(ClickSitelink3), string(ClickSitelink4),
+		string(ClickSitelink5), string(ClickSitelink6), string(ClickSitelink7), string(ClickSitelink8), string(ClickVCard),
+		string(ClickPhone), string(ClickDisplayURLPath), string(ClickMobileAppIcon), string(ClickButton), string(ClickUnknown),
+	},
+	FieldCriterionType: {
+		string(CriterionKeyword), string(CriterionAutotargeting), string(CriterionAudienceTarget), string(CriterionDynamicTextAdTarget),
+		string(CriterionSmartBannerFilter), string(CriterionWebpageFilter), string(CriterionFeedFilter), string(CriterionOfferRetargeting),
+	},
+	FieldCampaignType: {
+		string(CampaignTypeText), string(CampaignTypeMobileApp), string(CampaignTypeDynamicText), string(CampaignTypeSmart),
+		string(CampaignTypeCpmBanner), string(CampaignTypeCpmDeals), string(CampaignTypeCpmFrontpage), string(CampaignTypeCpmPrice),
+		string(CampaignTypeUnified), string(CampaignTypeMcBanner), string(CampaignTypeContentPromo),
+	},
+}
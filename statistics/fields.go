@@ -0,0 +1,243 @@
+package statistics
+
+// FieldKind тип значений поля, определяет допустимые операторы фильтрации.
+type FieldKind int
+
+const (
+	KindString  FieldKind = iota // Строка.
+	KindEnum                     // Значение из фиксированного набора.
+	KindID                       // Идентификатор.
+	KindInteger                  // Целое число.
+	KindFloat                    // Дробное число или процент.
+	KindMoney                    // Денежная сумма; в фильтрах указывается в микроединицах.
+	KindDate                     // Дата или период: день, неделя, месяц.
+)
+
+// FieldInfo описание поля отчета.
+type FieldInfo struct {
+	Name       Field
+	Kind       FieldKind
+	Metric     bool         // Поле является показателем, а не группировкой.
+	Filterable bool         // Поле можно использовать в SelectionCriteria.Filter.
+	Reports    []ReportType // Типы отчетов, в которых доступно поле. Пусто — во всех.
+	Values     []string     // Допустимые значения перечисления. Пусто — значения не проверяются.
+}
+
+// SupportedBy сообщает, доступно ли поле в отчете типа t.
+func (f FieldInfo) SupportedBy(t ReportType) bool {
+	if len(f.Reports) == 0 {
+		return true
+	}
+
+	for _, r := range f.Reports {
+		if r == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+const (
+	FieldAdFormat               Field = "AdFormat"
+	FieldAdGroupID              Field = "AdGroupId"
+	FieldAdGroupName            Field = "AdGroupName"
+	FieldAdID                   Field = "AdId"
+	FieldAdNetworkType          Field = "AdNetworkType"
+	FieldAge                    Field = "Age"
+	FieldAvgClickPosition       Field = "AvgClickPosition"
+	FieldAvgCpc                 Field = "AvgCpc"
+	FieldAvgCpm                 Field = "AvgCpm"
+	FieldAvgEffectiveBid        Field = "AvgEffectiveBid"
+	FieldAvgImpressionFrequency Field = "AvgImpressionFrequency"
+	FieldAvgImpressionPosition  Field = "AvgImpressionPosition"
+	FieldAvgPageviews           Field = "AvgPageviews"
+	FieldAvgTrafficVolume       Field = "AvgTrafficVolume"
+	FieldBounceRate             Field = "BounceRate"
+	FieldBounces                Field = "Bounces"
+	FieldCampaignID             Field = "CampaignId"
+	FieldCampaignName           Field = "CampaignName"
+	FieldCampaignType           Field = "CampaignType"
+	FieldCampaignURLPath        Field = "CampaignUrlPath"
+	FieldCarrierType            Field = "CarrierType"
+	FieldClickType              Field = "ClickType"
+	FieldClicks                 Field = "Clicks"
+	FieldClientLogin            Field = "ClientLogin"
+	FieldConversionRate         Field = "ConversionRate"
+	FieldConversions            Field = "Conversions"
+	FieldCost                   Field = "Cost"
+	FieldCostPerConversion      Field = "CostPerConversion"
+	FieldCriterion              Field = "Criterion"
+	FieldCriterionID            Field = "CriterionId"
+	FieldCriterionType          Field = "CriterionType"
+	FieldCtr                    Field = "Ctr"
+	FieldDate                   Field = "Date"
+	FieldDevice                 Field = "Device"
+	FieldExternalNetworkName    Field = "ExternalNetworkName"
+	FieldGender                 Field = "Gender"
+	FieldGoalsRoi               Field = "GoalsRoi"
+	FieldImpressionReach        Field = "ImpressionReach"
+	FieldImpressionShare        Field = "ImpressionShare"
+	FieldImpressions            Field = "Impressions"
+	FieldIncomeGrade            Field = "IncomeGrade"
+	FieldLocationOfPresenceID   Field = "LocationOfPresenceId"
+	FieldLocationOfPresenceName Field = "LocationOfPresenceName"
+	FieldMatchType              Field = "MatchType"
+	FieldMatchedKeyword         Field = "MatchedKeyword"
+	FieldMobilePlatform         Field = "MobilePlatform"
+	FieldMonth                  Field = "Month"
+	FieldPlacement              Field = "Placement"
+	FieldProfit                 Field = "Profit"
+	FieldQuarter                Field = "Quarter"
+	FieldQuery                  Field = "Query"
+	FieldRevenue                Field = "Revenue"
+	FieldRlAdjustmentID         Field = "RlAdjustmentId"
+	FieldSessions               Field = "Sessions"
+	FieldSlot                   Field = "Slot"
+	FieldTargetingCategory      Field = "TargetingCategory"
+	FieldTargetingLocationID    Field = "TargetingLocationId"
+	FieldTargetingLocationName  Field = "TargetingLocationName"
+	FieldWeek                   Field = "Week"
+	FieldWeightedCtr            Field = "WeightedCtr"
+	FieldWeightedImpressions    Field = "WeightedImpressions"
+	FieldYear                   Field = "Year"
+)
+
+func reports(types ...ReportType) []ReportType {
+	return types
+}
+
+// withoutAccount отчеты, в которых есть группировка по кампаниям.
+func withoutAccount() []ReportType {
+	return reports(CampaignPerformanceReport, AdgroupPerformanceReport, AdPerformanceReport, CriteriaPerformanceReport,
+		CustomReport, ReachAndFrequencyPerformanceReport, SearchQueryPerformanceReport)
+}
+
+// withAdGroups отчеты, в которых есть группировка по группам объявлений.
+func withAdGroups() []ReportType {
+	return reports(AdgroupPerformanceReport, AdPerformanceReport, CriteriaPerformanceReport,
+		CustomReport, ReachAndFrequencyPerformanceReport, SearchQueryPerformanceReport)
+}
+
+// withAds отчеты, в которых есть группировка по объявлениям.
+func withAds() []ReportType {
+	return reports(AdPerformanceReport, CustomReport, ReachAndFrequencyPerformanceReport, SearchQueryPerformanceReport)
+}
+
+// withCriteria отчеты, в которых есть группировка по условиям показа.
+func withCriteria() []ReportType {
+	return reports(CriteriaPerformanceReport, CustomReport, ReachAndFrequencyPerformanceReport, SearchQueryPerformanceReport)
+}
+
+// catalogue справочник полей отчетов, строится один раз при инициализации пакета.
+var catalogue = buildCatalogue() //nolint:gochecknoglobals
+
+// Catalogue возвращает копию справочника полей отчетов.
+func Catalogue() map[Field]FieldInfo {
+	out := make(map[Field]FieldInfo, len(catalogue))
+	for name, f := range catalogue {
+		f.Reports = append([]ReportType(nil), f.Reports...)
+		f.Values = append([]string(nil), f.Values...)
+		out[name] = f
+	}
+
+	return out
+}
+
+func buildCatalogue() map[Field]FieldInfo {
+	dim := func(name Field, kind FieldKind, types []ReportType) FieldInfo {
+		return FieldInfo{Name: name, Kind: kind, Filterable: true, Reports: types}
+	}
+	metric := func(name Field, kind FieldKind, types []ReportType) FieldInfo {
+		return FieldInfo{Name: name, Kind: kind, Metric: true, Filterable: true, Reports: types}
+	}
+	group := func(name Field, types []ReportType) FieldInfo {
+		return FieldInfo{Name: name, Kind: KindDate, Reports: types}
+	}
+
+	fields := []FieldInfo{
+		dim(FieldDate, KindDate, nil),
+		group(FieldWeek, nil),
+		group(FieldMonth, nil),
+		group(FieldQuarter, nil),
+		group(FieldYear, nil),
+		dim(FieldClientLogin, KindString, nil),
+		dim(FieldCampaignID, KindID, withoutAccount()),
+		dim(FieldCampaignName, KindString, withoutAccount()),
+		dim(FieldCampaignType, KindEnum, withoutAccount()),
+		dim(FieldCampaignURLPath, KindString, withoutAccount()),
+		dim(FieldAdGroupID, KindID, withAdGroups()),
+		dim(FieldAdGroupName, KindString, withAdGroups()),
+		dim(FieldAdID, KindID, withAds()),
+		dim(FieldAdFormat, KindEnum, withAds()),
+		dim(FieldCriterionID, KindID, withCriteria()),
+		dim(FieldCriterion, KindString, withCriteria()),
+		dim(FieldCriterionType, KindEnum, withCriteria()),
+		dim(FieldMatchType, KindEnum, withCriteria()),
+		dim(FieldMatchedKeyword, KindString, reports(SearchQueryPerformanceReport)),
+		dim(FieldQuery, KindString, reports(SearchQueryPerformanceReport)),
+		dim(FieldRlAdjustmentID, KindID, withCriteria()),
+		dim(FieldAdNetworkType, KindEnum, nil),
+		dim(FieldAge, KindEnum, nil),
+		dim(FieldGender, KindEnum, nil),
+		dim(FieldCarrierType, KindEnum, nil),
+		dim(FieldClickType, KindEnum, nil),
+		dim(FieldDevice, KindEnum, nil),
+		dim(FieldIncomeGrade, KindEnum, nil),
+		dim(FieldMobilePlatform, KindEnum, nil),
+		dim(FieldSlot, KindEnum, nil),
+		dim(FieldTargetingCategory, KindEnum, nil),
+		dim(FieldExternalNetworkName, KindString, nil),
+		dim(FieldPlacement, KindString, nil),
+		dim(FieldLocationOfPresenceID, KindID, nil),
+		dim(FieldLocationOfPresenceName, KindString, nil),
+		dim(FieldTargetingLocationID, KindID, nil),
+		dim(FieldTargetingLocationName, KindString, nil),
+		metric(FieldImpressions, KindInteger, nil),
+		metric(FieldClicks, KindInteger, nil),
+		metric(FieldCost, KindMoney, nil),
+		metric(FieldCtr, KindFloat, nil),
+		metric(FieldAvgCpc, KindMoney, nil),
+		metric(FieldAvgCpm, KindMoney, nil),
+		metric(FieldAvgEffectiveBid, KindMoney, nil),
+		metric(FieldAvgClickPosition, KindFloat, nil),
+		metric(FieldAvgImpressionPosition, KindFloat, nil),
+		metric(FieldAvgPageviews, KindFloat, nil),
+		metric(FieldAvgTrafficVolume, KindFloat, nil),
+		metric(FieldBounceRate, KindFloat, nil),
+		metric(FieldBounces, KindInteger, nil),
+		metric(FieldSessions, KindInteger, nil),
+		metric(FieldConversions, KindInteger, nil),
+		metric(FieldConversionRate, KindFloat, nil),
+		metric(FieldCostPerConversion, KindMoney, nil),
+		metric(FieldGoalsRoi, KindFloat, nil),
+		metric(FieldRevenue, KindMoney, nil),
+		metric(FieldProfit, KindMoney, nil),
+		metric(FieldImpressionShare, KindFloat, nil),
+		metric(FieldWeightedCtr, KindFloat, nil),
+		metric(FieldWeightedImpressions, KindInteger, nil),
+		metric(FieldImpressionReach, KindInteger, reports(ReachAndFrequencyPerformanceReport)),
+		metric(FieldAvgImpressionFrequency, KindFloat, reports(ReachAndFrequencyPerformanceReport)),
+	}
+
+	out := make(map[Field]FieldInfo, len(fields))
+	for _, f := range fields {
+		f.Values = enumValues[f.Name]
+		out[f.Name] = f
+	}
+
+	return out
+}
+
+// LookupField возвращает описание поля из справочника.
+func LookupField(name Field) (FieldInfo, bool) {
+	f, ok := catalogue[name]
+	if !ok {
+		return FieldInfo{}, false
+	}
+
+	f.Reports = append([]ReportType(nil), f.Reports...)
+	f.Values = append([]string(nil), f.Values...)
+
+	return f, true
+}
@@ -0,0 +1,47 @@
+package statistics
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCatalogueIsCopy(t *testing.T) {
+	c := Catalogue()
+
+	device := c[FieldDevice]
+	device.Values[0] = "CHANGED"
+	c[FieldDevice] = device
+	delete(c, FieldDate)
+
+	info, ok := LookupField(FieldDevice)
+	if !ok || info.Values[0] != string(DeviceDesktop) {
+		t.Errorf("LookupField(Device).Values = %v; catalogue was modified through a copy", info.Values)
+	}
+
+	if _, ok := LookupField(FieldDate); !ok {
+		t.Error("LookupField(Date) not found after deleting it from a copy")
+	}
+}
+
+func TestEnumFilters(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func() (Filter, error)
+		err   error
+	}{
+		{name: "slot", build: func() (Filter, error) { return SlotIn(SlotPremiumBlock, SlotOther) }},
+		{name: "click type", build: func() (Filter, error) { return ClickTypeIn(ClickTitle, ClickSitelink1) }},
+		{name: "ad format", build: func() (Filter, error) { return AdFormatIn(AdFormatText, AdFormatImage) }},
+		{name: "criterion type", build: func() (Filter, error) { return CriterionTypeIn(CriterionKeyword, CriterionAutotargeting) }},
+		{name: "unknown slot", build: func() (Filter, error) { return SlotIn("TOP") }, err: ErrInvalidFilter},
+		{name: "unknown criterion type", build: func() (Filter, error) { return EnumIn(FieldCriterionType, "PHRASE") }, err: ErrInvalidFilter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.build(); !errors.Is(err, tt.err) {
+				t.Errorf("error = %v; want %v", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,403 @@
+package statistics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidFilter ошибка проверки условия фильтрации.
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// microsPerUnit количество микроединиц в единице валюты. Денежные значения фильтров передаются в микроединицах.
+const microsPerUnit = 1_000_000
+
+// kindOperators допустимые операторы для каждого типа поля.
+var kindOperators = map[FieldKind][]FilterOperator{
+	KindID:      {Equals, NotEquals, In, NotIn},
+	KindEnum:    {Equals, NotEquals, In, NotIn},
+	KindDate:    {Equals, NotEquals, In, NotIn, LessThan, GreaterThan},
+	KindInteger: {Equals, NotEquals, In, NotIn, LessThan, GreaterThan},
+	KindFloat:   {Equals, NotEquals, In, NotIn, LessThan, GreaterThan},
+	KindMoney:   {Equals, NotEquals, In, NotIn, LessThan, GreaterThan},
+	KindString: {
+		Equals, NotEquals, In, NotIn,
+		StartsWithIgnoreCase, DoesNotStartWithIgnoreCase, StartsWithAnyIgnoreCase, DoesNotStartWithAllIgnoreCase,
+	},
+}
+
+// operatorSymbols краткая запись операторов для логов.
+var operatorSymbols = map[FilterOperator]string{
+	Equals:      "=",
+	NotEquals:   "!=",
+	LessThan:    "<",
+	GreaterThan: ">",
+}
+
+// SingleValue сообщает, принимает ли оператор ровно одно значение.
+func (o FilterOperator) SingleValue() bool {
+	switch o {
+	case In, NotIn, StartsWithAnyIgnoreCase, DoesNotStartWithAllIgnoreCase:
+		return false
+	}
+
+	return true
+}
+
+// Allows сообщает, допустим ли оператор для полей типа k.
+func (k FieldKind) Allows(op FilterOperator) bool {
+	for _, o := range kindOperators[k] {
+		if o == op {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validate проверяет условие по справочнику полей: поле, оператор, количество и формат значений.
+func (f Filter) Validate() error {
+	info, ok := LookupField(Field(f.Fields))
+	if !ok {
+		return fmt.Errorf("%w: unknown field %s", ErrInvalidFilter, f.Fields)
+	}
+
+	if !info.Filterable {
+		return fmt.Errorf("%w: field %s cannot be filtered", ErrInvalidFilter, f.Fields)
+	}
+
+	if !info.Kind.Allows(f.Operator) {
+		return fmt.Errorf("%w: operator %s is not allowed for %s", ErrInvalidFilter, f.Operator, f.Fields)
+	}
+
+	switch {
+	case len(f.Values) == 0:
+		return fmt.Errorf("%w: %s %s has no values", ErrInvalidFilter, f.Fields, f.Operator)
+	case f.Operator.SingleValue() && len(f.Values) > 1:
+		return fmt.Errorf("%w: %s %s takes one value, got %d", ErrInvalidFilter, f.Fields, f.Operator, len(f.Values))
+	}
+
+	for _, v := range f.Values {
+		if err := checkValue(info, v); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidFilter, f.Fields, err)
+		}
+	}
+
+	return nil
+}
+
+func checkValue(info FieldInfo, v string) error {
+	switch info.Kind {
+	case KindID:
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id <= 0 {
+			return fmt.Errorf("invalid id %q", v)
+		}
+	case KindInteger, KindMoney:
+		if _, err := strconv.ParseInt(v, 10, 64); err != nil {
+			return fmt.Errorf("invalid integer %q", v)
+		}
+	case KindFloat:
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			return fmt.Errorf("invalid number %q", v)
+		}
+	case KindDate:
+		if _, err := time.Parse(DateLayout, v); err != nil {
+			return fmt.Errorf("invalid date %q", v)
+		}
+	case KindEnum:
+		if len(info.Values) == 0 {
+			return nil
+		}
+
+		for _, allowed := range info.Values {
+			if v == allowed {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("unknown value %q", v)
+	case KindString:
+	}
+
+	return nil
+}
+
+// NewFilter создает условие и проверяет его по справочнику полей.
+func NewFilter(field Field, op FilterOperator, values ...string) (Filter, error) {
+	f := Filter{Fields: string(field), Operator: op, Values: values}
+
+	if err := f.Validate(); err != nil {
+		return Filter{}, err
+	}
+
+	return f, nil
+}
+
+func newKindFilter(kinds []FieldKind, field Field, op FilterOperator, values []string) (Filter, error) {
+	info, ok := LookupField(field)
+	if !ok {
+		return Filter{}, fmt.Errorf("%w: unknown field %s", ErrInvalidFilter, field)
+	}
+
+	for _, k := range kinds {
+		if info.Kind == k {
+			return NewFilter(field, op, values...)
+		}
+	}
+
+	return Filter{}, fmt.Errorf("%w: field %s has unsuitable type", ErrInvalidFilter, field)
+}
+
+// IDs создает условие по полю-идентификатору.
+func IDs(field Field, op FilterOperator, ids ...int64) (Filter, error) {
+	values := make([]string, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, strconv.FormatInt(id, 10))
+	}
+
+	return newKindFilter([]FieldKind{KindID}, field, op, values)
+}
+
+// IDIn условие «идентификатор из списка».
+func IDIn(field Field, ids ...int64) (Filter, error) {
+	return IDs(field, In, ids...)
+}
+
+// IDNotIn условие «идентификатор не из списка».
+func IDNotIn(field Field, ids ...int64) (Filter, error) {
+	return IDs(field, NotIn, ids...)
+}
+
+// Number создает условие по числовому полю. Для денежных полей значения указываются в единицах валюты
+// и переводятся в микроединицы, для целочисленных полей дробная часть не допускается.
+func Number(field Field, op FilterOperator, values ...float64) (Filter, error) {
+	info, ok := LookupField(field)
+	if !ok {
+		return Filter{}, fmt.Errorf("%w: unknown field %s", ErrInvalidFilter, field)
+	}
+
+	out := make([]string, 0, len(values))
+
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return Filter{}, fmt.Errorf("%w: %s: invalid number %v", ErrInvalidFilter, field, v)
+		}
+
+		switch info.Kind {
+		case KindMoney:
+			out = append(out, strconv.FormatInt(ToMicros(v), 10))
+		case KindInteger:
+			if v != math.Trunc(v) {
+				return Filter{}, fmt.Errorf("%w: %s: %v is not an integer", ErrInvalidFilter, field, v)
+			}
+
+			out = append(out, strconv.FormatInt(int64(v), 10))
+		case KindFloat:
+			out = append(out, strconv.FormatFloat(v, 'f', -1, 64))
+		default:
+			return Filter{}, fmt.Errorf("%w: field %s is not numeric", ErrInvalidFilter, field)
+		}
+	}
+
+	return NewFilter(field, op, out...)
+}
+
+// Micros создает условие по денежному полю со значениями в микроединицах.
+func Micros(field Field, op FilterOperator, micros ...int64) (Filter, error) {
+	values := make([]string, 0, len(micros))
+	for _, m := range micros {
+		values = append(values, strconv.FormatInt(m, 10))
+	}
+
+	return newKindFilter([]FieldKind{KindMoney}, field, op, values)
+}
+
+// ToMicros переводит сумму в единицах валюты в микроединицы.
+func ToMicros(amount float64) int64 {
+	return int64(math.Round(amount * microsPerUnit))
+}
+
+// FromMicros переводит сумму в микроединицах в единицы валюты.
+func FromMicros(micros int64) float64 {
+	return float64(micros) / microsPerUnit
+}
+
+// Dates создает условие по полю-дате.
+func Dates(field Field, op FilterOperator, dates ...time.Time) (Filter, error) {
+	values := make([]string, 0, len(dates))
+	for _, d := range dates {
+		values = append(values, d.Format(DateLayout))
+	}
+
+	return newKindFilter([]FieldKind{KindDate}, field, op, values)
+}
+
+// Text создает условие по строковому полю.
+func Text(field Field, op FilterOperator, values ...string) (Filter, error) {
+	return newKindFilter([]FieldKind{KindString}, field, op, values)
+}
+
+// EnumIn условие «значение перечисления из списка».
+func EnumIn(field Field, values ...string) (Filter, error) {
+	return newKindFilter([]FieldKind{KindEnum}, field, In, values)
+}
+
+// EnumNotIn условие «значение перечисления не из списка».
+func EnumNotIn(field Field, values ...string) (Filter, error) {
+	return newKindFilter([]FieldKind{KindEnum}, field, NotIn, values)
+}
+
+// DeviceIn условие по типу устройства.
+func DeviceIn(devices ...Device) (Filter, error) {
+	values := make([]string, 0, len(devices))
+	for _, d := range devices {
+		values = append(values, string(d))
+	}
+
+	return EnumIn(FieldDevice, values...)
+}
+
+// AdNetworkTypeIn условие по типу площадки.
+func AdNetworkTypeIn(types ...AdNetworkType) (Filter, error) {
+	values := make([]string, 0, len(types))
+	for _, t := range types {
+		values = append(values, string(t))
+	}
+
+	return EnumIn(FieldAdNetworkType, values...)
+}
+
+// CampaignTypeIn условие по типу кампании.
+func CampaignTypeIn(types ...CampaignType) (Filter, error) {
+	values := make([]string, 0, len(types))
+	for _, t := range types {
+		values = append(values, string(t))
+	}
+
+	return EnumIn(FieldCampaignType, values...)
+}
+
+// GenderIn условие по полу.
+func GenderIn(genders ...Gender) (Filter, error) {
+	values := make([]string, 0, len(genders))
+	for _, g := range genders {
+		values = append(values, string(g))
+	}
+
+	return EnumIn(FieldGender, values...)
+}
+
+// AgeIn условие по возрастной группе.
+func AgeIn(ages ...Age) (Filter, error) {
+	values := make([]string, 0, len(ages))
+	for _, a := range ages {
+		values = append(values, string(a))
+	}
+
+	return EnumIn(FieldAge, values...)
+}
+
+// MatchTypeIn условие по типу соответствия запроса ключевой фразе.
+func MatchTypeIn(types ...MatchType) (Filter, error) {
+	values := make([]string, 0, len(types))
+	for _, t := range types {
+		values = append(values, string(t))
+	}
+
+	return EnumIn(FieldMatchType, values...)
+}
+
+// SlotIn условие по блоку показа.
+func SlotIn(slots ...Slot) (Filter, error) {
+	values := make([]string, 0, len(slots))
+	for _, sl := range slots {
+		values = append(values, string(sl))
+	}
+
+	return EnumIn(FieldSlot, values...)
+}
+
+// ClickTypeIn условие по месту клика.
+func ClickTypeIn(types ...ClickType) (Filter, error) {
+	values := make([]string, 0, len(types))
+	for _, t := range types {
+		values = append(values, string(t))
+	}
+
+	return EnumIn(FieldClickType, values...)
+}
+
+// AdFormatIn условие по формату объявления.
+func AdFormatIn(formats ...AdFormat) (Filter, error) {
+	values := make([]string, 0, len(formats))
+	for _, f := range formats {
+		values = append(values, string(f))
+	}
+
+	return EnumIn(FieldAdFormat, values...)
+}
+
+// CriterionTypeIn условие по типу условия показа.
+func CriterionTypeIn(types ...CriterionType) (Filter, error) {
+	values := make([]string, 0, len(types))
+	for _, t := range types {
+		values = append(values, string(t))
+	}
+
+	return EnumIn(FieldCriterionType, values...)
+}
+
+// String возвращает условие в читаемом виде, например «CampaignId IN (1, 2)» или «Cost > 1000000».
+func (f Filter) String() string {
+	op := string(f.Operator)
+	if s, ok := operatorSymbols[f.Operator]; ok {
+		op = s
+	}
+
+	info, known := LookupField(Field(f.Fields))
+	quote := !known || info.Kind == KindString || info.Kind == KindDate
+
+	values := make([]string, 0, len(f.Values))
+
+	for _, v := range f.Values {
+		if quote {
+			v = strconv.Quote(v)
+		}
+
+		values = append(values, v)
+	}
+
+	if f.Operator.SingleValue() && len(values) == 1 {
+		return fmt.Sprintf("%s %s %s", f.Fields, op, values[0])
+	}
+
+	return fmt.Sprintf("%s %s (%s)", f.Fields, op, strings.Join(values, ", "))
+}
+
+// Filters набор условий, объединяемых через И.
+type Filters []Filter
+
+// String возвращает условия в читаемом виде, объединенные через AND.
+func (fs Filters) String() string {
+	parts := make([]string, 0, len(fs))
+	for _, f := range fs {
+		parts = append(parts, f.String())
+	}
+
+	return strings.Join(parts, " AND ")
+}
+
+// Validate проверяет все условия набора.
+func (fs Filters) Validate() error {
+	for _, f := range fs {
+		if err := f.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,183 @@
+package statistics
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MaxGoals максимальное количество целей в одном отчете.
+const MaxGoals = 10
+
+// Столбцы длинного формата, добавляемые GoalUnpivot.
+const (
+	ColumnGoalID           = "GoalId"
+	ColumnAttributionModel = "AttributionModel"
+)
+
+var ErrNoGoalColumns = errors.New("report has no goal columns")
+
+// goalFields показатели, которые при заданных Goals возвращаются отдельно по каждой цели и модели атрибуции.
+var goalFields = []Field{FieldConversions, FieldConversionRate, FieldCostPerConversion, FieldGoalsRoi, FieldRevenue, FieldProfit}
+
+// GoalColumn столбец показателя по цели вида <Field>_<GoalId>_<AttributionModel>, например Conversions_12345_LSC.
+type GoalColumn struct {
+	Field Field
+	Goal  string
+	Model AttributionModel
+}
+
+// ParseGoalColumn разбирает название столбца показателя по цели.
+func ParseGoalColumn(name string) (GoalColumn, bool) {
+	parts := strings.Split(name, "_")
+	if len(parts) != 3 || parts[1] == "" || parts[2] == "" {
+		return GoalColumn{}, false
+	}
+
+	for _, f := range goalFields {
+		if parts[0] == string(f) {
+			return GoalColumn{Field: f, Goal: parts[1], Model: AttributionModel(parts[2])}, true
+		}
+	}
+
+	return GoalColumn{}, false
+}
+
+// SplitGoals разбивает определение с количеством целей больше MaxGoals на несколько определений.
+// К имени каждого отчета добавляется суффикс _goals<N>. Определение без лишних целей возвращается как есть.
+func SplitGoals(def ReportDefinition) []ReportDefinition {
+	if def.Goals == nil || len(*def.Goals) <= MaxGoals {
+		return []ReportDefinition{def}
+	}
+
+	goals := *def.Goals
+
+	var out []ReportDefinition
+
+	for i := 0; i < len(goals); i += MaxGoals {
+		end := i + MaxGoals
+		if end > len(goals) {
+			end = len(goals)
+		}
+
+		part := def.clone()
+		chunk := append([]string(nil), goals[i:end]...)
+		part.Goals = &chunk
+		part.ReportName = fmt.Sprintf("%s_goals%d", def.ReportName, i/MaxGoals+1)
+		out = append(out, part)
+	}
+
+	return out
+}
+
+type goalKey struct {
+	goal  string
+	model AttributionModel
+}
+
+// GoalUnpivot преобразует строки отчета с целями в длинный формат: столбцы без цели, GoalId,
+// AttributionModel и показатели по цели. Каждая строка отчета дает по строке на пару цель — модель.
+//
+// Группировки (Date, CampaignId и т. п.) повторяются в каждой строке, а общие показатели строки
+// (Cost, Clicks, Impressions и другие показатели справочника без цели) — только в строке первой пары
+// цель — модель; в остальных они равны «--». Поэтому сумма общих показателей по длинному формату
+// совпадает с исходным отчетом. Столбцы, которых нет в справочнике полей, считаются группировками.
+type GoalUnpivot struct {
+	header  []string
+	keys    []int
+	base    []bool // base[i] — столбец keys[i] является общим показателем строки.
+	pairs   []goalKey
+	metrics []Field
+	columns map[goalKey]map[Field]int
+}
+
+// NewGoalUnpivot разбирает заголовок отчета. Возвращает ErrNoGoalColumns, если столбцов по целям нет.
+func NewGoalUnpivot(header []string) (*GoalUnpivot, error) {
+	u := &GoalUnpivot{columns: map[goalKey]map[Field]int{}}
+	seen := map[Field]bool{}
+
+	for i, name := range header {
+		col, ok := ParseGoalColumn(name)
+		if !ok {
+			u.keys = append(u.keys, i)
+			u.base = append(u.base, catalogue[Field(name)].Metric)
+			u.header = append(u.header, name)
+
+			continue
+		}
+
+		key := goalKey{goal: col.Goal, model: col.Model}
+		if _, ok := u.columns[key]; !ok {
+			u.columns[key] = map[Field]int{}
+			u.pairs = append(u.pairs, key)
+		}
+
+		u.columns[key][col.Field] = i
+
+		if !seen[col.Field] {
+			seen[col.Field] = true
+			u.metrics = append(u.metrics, col.Field)
+		}
+	}
+
+	if len(u.pairs) == 0 {
+		return nil, ErrNoGoalColumns
+	}
+
+	u.header = append(u.header, ColumnGoalID, ColumnAttributionModel)
+	for _, f := range u.metrics {
+		u.header = append(u.header, string(f))
+	}
+
+	return u, nil
+}
+
+// Header возвращает заголовок длинного формата.
+func (u *GoalUnpivot) Header() []string {
+	return u.header
+}
+
+// Rows преобразует строку отчета в строки длинного формата. Отсутствующие показатели заполняются «--»,
+// как пустые значения в отчетах Директа.
+func (u *GoalUnpivot) Rows(row []string) [][]string {
+	out := make([][]string, 0, len(u.pairs))
+
+	for n, key := range u.pairs {
+		line := make([]string, 0, len(u.header))
+
+		for j, i := range u.keys {
+			if n > 0 && u.base[j] {
+				line = append(line, "--")
+
+				continue
+			}
+
+			line = append(line, cell(row, i))
+		}
+
+		line = append(line, key.goal, string(key.model))
+
+		for _, f := range u.metrics {
+			i, ok := u.columns[key][f]
+			if !ok {
+				line = append(line, "--")
+
+				continue
+			}
+
+			line = append(line, cell(row, i))
+		}
+
+		out = append(out, line)
+	}
+
+	return out
+}
+
+func cell(row []string, i int) string {
+	if i < len(row) {
+		return row[i]
+	}
+
+	return ""
+}
@@ -0,0 +1,117 @@
+package statistics
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestParseGoalColumn(t *testing.T) {
+	tests := []struct {
+		name string
+		want GoalColumn
+		ok   bool
+	}{
+		{name: "Conversions_12345_LSC", want: GoalColumn{Field: FieldConversions, Goal: "12345", Model: "LSC"}, ok: true},
+		{name: "Revenue_1_AUTO", want: GoalColumn{Field: FieldRevenue, Goal: "1", Model: "AUTO"}, ok: true},
+		{name: "Conversions"},
+		{name: "Conversions_12345"},
+		{name: "Conversions__LSC"},
+		{name: "Conversions_12345_"},
+		{name: "Clicks_12345_LSC"},
+		{name: "Conversions_12345_LSC_extra"},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseGoalColumn(tt.name)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseGoalColumn(%q) = %+v, %v; want %+v, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestSplitGoals(t *testing.T) {
+	goals := func(n int) *[]string {
+		out := make([]string, n)
+		for i := range out {
+			out[i] = fmt.Sprint(i + 1)
+		}
+
+		return &out
+	}
+
+	tests := []struct {
+		name  string
+		goals *[]string
+		names []string
+		sizes []int
+	}{
+		{name: "no goals", names: []string{"report"}, sizes: []int{0}},
+		{name: "max goals", goals: goals(MaxGoals), names: []string{"report"}, sizes: []int{MaxGoals}},
+		{name: "split", goals: goals(25), names: []string{"report_goals1", "report_goals2", "report_goals3"}, sizes: []int{10, 10, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			def := ReportDefinition{ReportName: "report", Goals: tt.goals}
+			parts := SplitGoals(def)
+
+			var (
+				names []string
+				sizes []int
+				all   []string
+			)
+
+			for _, part := range parts {
+				names = append(names, part.ReportName)
+
+				if part.Goals == nil {
+					sizes = append(sizes, 0)
+
+					continue
+				}
+
+				sizes = append(sizes, len(*part.Goals))
+				all = append(all, *part.Goals...)
+			}
+
+			if !reflect.DeepEqual(names, tt.names) || !reflect.DeepEqual(sizes, tt.sizes) {
+				t.Errorf("parts = %v %v; want %v %v", names, sizes, tt.names, tt.sizes)
+			}
+
+			if tt.goals != nil && !reflect.DeepEqual(all, *tt.goals) {
+				t.Errorf("goals = %v; want %v", all, *tt.goals)
+			}
+		})
+	}
+}
+
+func TestGoalUnpivot(t *testing.T) {
+	header := []string{"Date", "CampaignId", "Cost", "Clicks", "Conversions_1_LSC", "Revenue_1_LSC", "Conversions_2_LSC", "Conversions_1_FC"}
+
+	u, err := NewGoalUnpivot(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantHeader := []string{"Date", "CampaignId", "Cost", "Clicks", ColumnGoalID, ColumnAttributionModel, "Conversions", "Revenue"}
+	if !reflect.DeepEqual(u.Header(), wantHeader) {
+		t.Errorf("Header() = %v; want %v", u.Header(), wantHeader)
+	}
+
+	rows := u.Rows([]string{"2024-01-01", "7", "1500", "30", "3", "900", "1", "2"})
+	want := [][]string{
+		{"2024-01-01", "7", "1500", "30", "1", "LSC", "3", "900"},
+		{"2024-01-01", "7", "--", "--", "2", "LSC", "1", "--"},
+		{"2024-01-01", "7", "--", "--", "1", "FC", "2", "--"},
+	}
+
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("Rows() = %v; want %v", rows, want)
+	}
+
+	if _, err := NewGoalUnpivot([]string{"Date", "Clicks"}); !errors.Is(err, ErrNoGoalColumns) {
+		t.Errorf("NewGoalUnpivot() without goal columns error = %v; want %v", err, ErrNoGoalColumns)
+	}
+}
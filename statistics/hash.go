@@ -0,0 +1,49 @@
+package statistics
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+)
+
+// Canonical возвращает определение без полей, не влияющих на данные отчета: имени и постраничной выборки.
+// Значения фильтров IN и NOT_IN и сами фильтры упорядочиваются, чтобы равные по смыслу определения совпадали.
+func (d ReportDefinition) Canonical() ReportDefinition {
+	out := d.clone()
+	out.ReportName = ""
+	out.Page = nil
+
+	if out.Selection != nil {
+		for i := range out.Selection.Filter {
+			if !out.Selection.Filter[i].Operator.SingleValue() {
+				sort.Strings(out.Selection.Filter[i].Values)
+			}
+		}
+
+		sort.SliceStable(out.Selection.Filter, func(i, j int) bool {
+			a, b := out.Selection.Filter[i], out.Selection.Filter[j]
+			if a.Fields != b.Fields {
+				return a.Fields < b.Fields
+			}
+
+			return a.Operator < b.Operator
+		})
+	}
+
+	return out
+}
+
+// Hash возвращает SHA-256 канонического определения вместе с логином и заголовками запроса,
+// влияющими на содержимое отчета.
+func Hash(def ReportDefinition, login string, headers map[string]string) string {
+	data, _ := json.Marshal(struct {
+		Login      string
+		Headers    map[string]string
+		Definition ReportDefinition
+	}{login, headers, def.Canonical()})
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,50 @@
+package statistics
+
+import "time"
+
+// Базовые показатели, которые входят во все ежедневные отчеты.
+var dailyMetrics = []Field{FieldImpressions, FieldClicks, FieldCost, FieldSessions, FieldBounces, FieldConversions}
+
+func daily(t ReportType, from, to time.Time, dims ...Field) *ReportBuilder {
+	b := NewReport(t).Dates(from, to).Fields(FieldDate).Fields(dims...).Fields(dailyMetrics...)
+
+	return b.OrderBy(FieldDate, ASCENDING)
+}
+
+// DailyAccount ежедневная статистика по аккаунту.
+func DailyAccount(from, to time.Time) *ReportBuilder {
+	return daily(AccountPerformanceReport, from, to)
+}
+
+// DailyCampaigns ежедневная статистика по кампаниям.
+func DailyCampaigns(from, to time.Time) *ReportBuilder {
+	return daily(CampaignPerformanceReport, from, to, FieldCampaignID, FieldCampaignName, FieldCampaignType)
+}
+
+// DailyAdGroups ежедневная статистика по группам объявлений.
+func DailyAdGroups(from, to time.Time) *ReportBuilder {
+	return daily(AdgroupPerformanceReport, from, to, FieldCampaignID, FieldAdGroupID, FieldAdGroupName)
+}
+
+// DailyAds ежедневная статистика по объявлениям.
+func DailyAds(from, to time.Time) *ReportBuilder {
+	return daily(AdPerformanceReport, from, to, FieldCampaignID, FieldAdGroupID, FieldAdID, FieldAdNetworkType)
+}
+
+// DailyCriteria ежедневная статистика по условиям показа.
+func DailyCriteria(from, to time.Time) *ReportBuilder {
+	return daily(CriteriaPerformanceReport, from, to,
+		FieldCampaignID, FieldAdGroupID, FieldCriterionID, FieldCriterion, FieldCriterionType)
+}
+
+// DailySearchQueries ежедневная статистика по поисковым запросам.
+func DailySearchQueries(from, to time.Time) *ReportBuilder {
+	return daily(SearchQueryPerformanceReport, from, to,
+		FieldCampaignID, FieldAdGroupID, FieldCriterionID, FieldQuery, FieldMatchType)
+}
+
+// DailyDevicesAndRegions ежедневная статистика по кампаниям в разрезе устройств и регионов.
+func DailyDevicesAndRegions(from, to time.Time) *ReportBuilder {
+	return daily(CustomReport, from, to,
+		FieldCampaignID, FieldDevice, FieldLocationOfPresenceID, FieldTargetingLocationID)
+}
@@ -0,0 +1,43 @@
+package statistics
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPresetsJSON(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 6)
+
+	presets := map[string]func(from, to time.Time) *ReportBuilder{
+		"DailyAccount":           DailyAccount,
+		"DailyCampaigns":         DailyCampaigns,
+		"DailyAdGroups":          DailyAdGroups,
+		"DailyAds":               DailyAds,
+		"DailyCriteria":          DailyCriteria,
+		"DailySearchQueries":     DailySearchQueries,
+		"DailyDevicesAndRegions": DailyDevicesAndRegions,
+	}
+
+	for name, preset := range presets {
+		t.Run(name, func(t *testing.T) {
+			def, err := preset(from, to).Name(name).Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := json.Marshal(def)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, want := range []string{`"OrderBy":[{"Field":"Date","SortOrder":"ASCENDING"}]`, `"DateFrom":"2024-01-01"`, `"DateTo":"2024-01-07"`} {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("JSON does not contain %s:\n%s", want, data)
+				}
+			}
+		})
+	}
+}
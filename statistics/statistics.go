@@ -56,7 +56,7 @@ const (
 )
 
 type OrderBy struct {
-	Field     string           `json:"field"`
+	Field     string           `json:"Field"`
 	SortOrder OrderBySortOrder `json:"SortOrder"`
 }
 
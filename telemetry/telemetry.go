@@ -0,0 +1,239 @@
+package telemetry
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	sdk "github.com/mg-realcom/yandex-direct-sdk"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/mg-realcom/yandex-direct-sdk/telemetry"
+
+const (
+	AttrService    = attribute.Key("direct.service")
+	AttrMethod     = attribute.Key("direct.method")
+	AttrLogin      = attribute.Key("direct.login")
+	AttrRequestID  = attribute.Key("direct.request_id")
+	AttrUnitsSpent = attribute.Key("direct.units.spent")
+	AttrUnitsRest  = attribute.Key("direct.units.rest")
+	AttrUnitsLimit = attribute.Key("direct.units.limit")
+	AttrStatus     = attribute.Key("http.status_code")
+	AttrReportName = attribute.Key("direct.report.name")
+	AttrReportType = attribute.Key("direct.report.type")
+	AttrRetryCount = attribute.Key("direct.retry_count")
+	AttrQueueWait  = attribute.Key("direct.report.queue_wait_seconds")
+)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// Option настраивает инструментирование.
+type Option func(*config)
+
+// WithTracerProvider задает провайдер трассировки. По умолчанию используется глобальный.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithMeterProvider задает провайдер метрик. По умолчанию используется глобальный.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = mp
+	}
+}
+
+type instruments struct {
+	tracer     trace.Tracer
+	latency    metric.Float64Histogram
+	units      metric.Int64Counter
+	queueWait  metric.Float64Histogram
+	downloaded metric.Int64Counter
+}
+
+// Middleware создает sdk.Middleware, который открывает span на каждый вызов API и пишет метрики
+// задержки, расхода баллов, ожидания в очереди отчетов и объема скачанных данных.
+func Middleware(opts ...Option) (sdk.Middleware, error) {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	inst, err := newInstruments(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(next http.RoundTripper) http.RoundTripper {
+		return sdk.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return inst.roundTrip(next, req)
+		})
+	}, nil
+}
+
+func newInstruments(cfg config) (*instruments, error) {
+	meter := cfg.meterProvider.Meter(instrumentationName)
+
+	latency, err := meter.Float64Histogram("direct.client.duration",
+		metric.WithDescription("Длительность вызова API Директа, включая скачивание ответа."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, fmt.Errorf("latency histogram: %w", err)
+	}
+
+	units, err := meter.Int64Counter("direct.units.spent",
+		metric.WithDescription("Израсходованные баллы API."),
+		metric.WithUnit("{unit}"))
+	if err != nil {
+		return nil, fmt.Errorf("units counter: %w", err)
+	}
+
+	queueWait, err := meter.Float64Histogram("direct.report.queue_wait",
+		metric.WithDescription("Время ожидания отчета в очереди офлайн-формирования."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, fmt.Errorf("queue wait histogram: %w", err)
+	}
+
+	downloaded, err := meter.Int64Counter("direct.client.downloaded",
+		metric.WithDescription("Объем скачанных данных; сжатые ответы учитываются до распаковки."),
+		metric.WithUnit("By"))
+	if err != nil {
+		return nil, fmt.Errorf("downloaded counter: %w", err)
+	}
+
+	return &instruments{
+		tracer:     cfg.tracerProvider.Tracer(instrumentationName),
+		latency:    latency,
+		units:      units,
+		queueWait:  queueWait,
+		downloaded: downloaded,
+	}, nil
+}
+
+func (inst *instruments) roundTrip(next http.RoundTripper, req *http.Request) (*http.Response, error) {
+	info, _ := sdk.CallInfoFromContext(req.Context())
+	common := callAttributes(info)
+
+	ctx, span := inst.tracer.Start(req.Context(), spanName(info),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(common...),
+		trace.WithAttributes(
+			AttrReportName.String(info.ReportName),
+			AttrRetryCount.Int(info.Retries()),
+			AttrQueueWait.Float64(info.QueueWait.Seconds()),
+		),
+	)
+	start := time.Now()
+
+	resp, err := next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.End()
+		inst.latency.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(common...))
+
+		return nil, err //nolint:wrapcheck
+	}
+
+	status := AttrStatus.Int(resp.StatusCode)
+	span.SetAttributes(status, AttrRequestID.String(resp.Header.Get(sdk.HeaderRequestID)))
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+
+	if units, ok := sdk.ParseUnits(resp.Header); ok {
+		span.SetAttributes(AttrUnitsSpent.Int64(units.Spent), AttrUnitsRest.Int64(units.Rest), AttrUnitsLimit.Int64(units.Limit))
+		inst.units.Add(ctx, units.Spent, metric.WithAttributes(common...))
+	}
+
+	if info.Service == "reports" && resp.StatusCode == http.StatusOK {
+		inst.queueWait.Record(ctx, info.QueueWait.Seconds(), metric.WithAttributes(AttrLogin.String(info.Login), AttrReportType.String(string(info.ReportType))))
+	}
+
+	resp.Body = &body{
+		ReadCloser: resp.Body,
+		finish: func(n int64) {
+			attrs := metric.WithAttributes(append(common, status)...)
+			inst.downloaded.Add(ctx, n, attrs)
+			inst.latency.Record(ctx, time.Since(start).Seconds(), attrs)
+			span.End()
+		},
+	}
+
+	return resp, nil
+}
+
+func spanName(info sdk.CallInfo) string {
+	if info.Service == "" {
+		return "direct.request"
+	}
+
+	return "direct." + info.Service + "." + info.Method
+}
+
+func callAttributes(info sdk.CallInfo) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		AttrService.String(info.Service),
+		AttrMethod.String(info.Method),
+		AttrLogin.String(info.Login),
+	}
+
+	if info.ReportType != "" {
+		attrs = append(attrs, AttrReportType.String(string(info.ReportType)))
+	}
+
+	return attrs
+}
+
+// body считает прочитанные байты и завершает span при закрытии или окончании тела ответа. Если тело
+// распаковано клиентом, учитываются байты, полученные из сети.
+type body struct {
+	io.ReadCloser
+	n      int64
+	once   sync.Once
+	finish func(n int64)
+}
+
+func (b *body) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+
+	if err == io.EOF { //nolint:errorlint
+		b.once.Do(b.done)
+	}
+
+	return n, err //nolint:wrapcheck
+}
+
+func (b *body) Close() error {
+	b.once.Do(b.done)
+
+	return b.ReadCloser.Close() //nolint:wrapcheck
+}
+
+func (b *body) done() {
+	if wc, ok := b.ReadCloser.(sdk.WireCounter); ok {
+		b.finish(wc.WireBytes())
+
+		return
+	}
+
+	b.finish(b.n)
+}
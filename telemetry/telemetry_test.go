@@ -0,0 +1,260 @@
+package telemetry
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	sdk "github.com/mg-realcom/yandex-direct-sdk"
+	"github.com/mg-realcom/yandex-direct-sdk/statistics"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type stubResponse struct {
+	status int
+	header http.Header
+	body   string
+}
+
+// stubTransport возвращает ответы по порядку, последний ответ повторяется.
+type stubTransport struct {
+	responses []stubResponse
+}
+
+func (s *stubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := s.responses[0]
+	if len(s.responses) > 1 {
+		s.responses = s.responses[1:]
+	}
+
+	header := http.Header{}
+	for k, v := range r.header {
+		header[k] = v
+	}
+
+	return &http.Response{
+		StatusCode: r.status,
+		Status:     http.StatusText(r.status),
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(r.body)),
+		Request:    req,
+	}, nil
+}
+
+func newTracedClient(t *testing.T, responses ...stubResponse) (*sdk.Client, *tracetest.SpanRecorder) {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	mw, err := Middleware(WithTracerProvider(provider))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := "token"
+	c := sdk.NewClient(&http.Client{Transport: &stubTransport{responses: responses}}, "client-login", &token, &sdk.App{}, false, nil)
+	c.Use(mw)
+
+	return c, recorder
+}
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	out := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		out[kv.Key] = kv.Value
+	}
+
+	return out
+}
+
+func TestReportSpans(t *testing.T) {
+	c, recorder := newTracedClient(t,
+		stubResponse{status: http.StatusCreated, header: http.Header{"Retryin": {"1"}, "Reportsinqueue": {"1"}}},
+		stubResponse{
+			status: http.StatusOK,
+			header: http.Header{"Units": {"10/19990/20000"}, "Requestid": {"42"}},
+			body:   "Date\tClicks\n2024-01-01\t5\n",
+		},
+		stubResponse{status: http.StatusOK, body: "Date\tClicks\n"},
+	)
+
+	def := statistics.NewReport(statistics.CampaignPerformanceReport).
+		DateRange("2024-01-01", "2024-01-01").
+		Fields(statistics.FieldDate, statistics.FieldClicks).
+		MustBuild()
+
+	files, err := c.GetFiles(context.Background(), t.TempDir(), def)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(files) != 1 {
+		t.Fatalf("got %d files, want 1", len(files))
+	}
+
+	spans := recorder.Ended()
+	if len(spans) < 2 {
+		t.Fatalf("got %d spans, want at least 2", len(spans))
+	}
+
+	tests := []struct {
+		name      string
+		retries   int64
+		queueWait float64
+		units     bool
+	}{
+		{name: "queued", retries: 0, queueWait: 0},
+		{name: "downloaded", retries: 1, queueWait: 1, units: true},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			span := spans[i]
+			if span.Name() != "direct.reports.get" {
+				t.Errorf("span name %q", span.Name())
+			}
+
+			attrs := attributes(span)
+
+			want := map[attribute.Key]attribute.Value{
+				AttrService:    attribute.StringValue("reports"),
+				AttrMethod:     attribute.StringValue("get"),
+				AttrLogin:      attribute.StringValue("client-login"),
+				AttrReportType: attribute.StringValue(string(statistics.CampaignPerformanceReport)),
+				AttrRetryCount: attribute.Int64Value(tt.retries),
+				AttrQueueWait:  attribute.Float64Value(tt.queueWait),
+			}
+
+			for k, v := range want {
+				if got, ok := attrs[k]; !ok || got != v {
+					t.Errorf("%s = %v, want %v", k, got.Emit(), v.Emit())
+				}
+			}
+
+			if _, ok := attrs[AttrUnitsSpent]; ok != tt.units {
+				t.Errorf("units attribute present = %v, want %v", ok, tt.units)
+			}
+		})
+	}
+
+	if got := attributes(spans[1])[AttrRequestID].AsString(); got != "42" {
+		t.Errorf("request id %q", got)
+	}
+}
+
+func TestCallInfoSpan(t *testing.T) {
+	c, recorder := newTracedClient(t, stubResponse{status: http.StatusOK, body: `{"result":{"Clients":[]}}`})
+
+	if err := c.Call(context.Background(), "clients", "get", struct{}{}, &struct{}{}); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+
+	if spans[0].Name() != "direct.clients.get" {
+		t.Errorf("span name %q", spans[0].Name())
+	}
+
+	attrs := attributes(spans[0])
+	if attrs[AttrLogin].AsString() != "client-login" || attrs[AttrRetryCount].AsInt64() != 0 {
+		t.Errorf("unexpected attributes %v", spans[0].Attributes())
+	}
+
+	if _, ok := attrs[AttrReportType]; ok {
+		t.Error("report type set for a non-report call")
+	}
+}
+
+// meterProvider запоминает суммы счетчиков Int64Counter по имени.
+type meterProvider struct {
+	noop.MeterProvider
+	counters map[string]*counter
+}
+
+func (p *meterProvider) Meter(string, ...metric.MeterOption) metric.Meter {
+	return meter{p: p}
+}
+
+type meter struct {
+	noop.Meter
+	p *meterProvider
+}
+
+func (m meter) Int64Counter(name string, _ ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	c := &counter{}
+	m.p.counters[name] = c
+
+	return c, nil
+}
+
+type counter struct {
+	noop.Int64Counter
+	sum int64
+}
+
+func (c *counter) Add(_ context.Context, n int64, _ ...metric.AddOption) {
+	c.sum += n
+}
+
+func TestDownloadedCountsWireBytes(t *testing.T) {
+	const body = `{"result":{"Clients":[{"Login":"client-login","Currency":"RUB"}]}}`
+
+	var gz bytes.Buffer
+
+	zw := gzip.NewWriter(&gz)
+	if _, err := zw.Write([]byte(body)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		resp stubResponse
+		want int64
+	}{
+		{name: "plain", resp: stubResponse{status: http.StatusOK, body: body}, want: int64(len(body))},
+		{
+			name: "gzip",
+			resp: stubResponse{status: http.StatusOK, header: http.Header{"Content-Encoding": {"gzip"}}, body: gz.String()},
+			want: int64(gz.Len()),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mp := &meterProvider{counters: map[string]*counter{}}
+
+			mw, err := Middleware(WithMeterProvider(mp))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			token := "token"
+			c := sdk.NewClient(&http.Client{Transport: &stubTransport{responses: []stubResponse{tt.resp}}}, "client-login", &token, &sdk.App{}, false, nil)
+			c.Use(mw)
+
+			if err := c.Call(context.Background(), "clients", "get", struct{}{}, &struct{}{}); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := mp.counters["direct.client.downloaded"].sum; got != tt.want {
+				t.Errorf("downloaded = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,320 @@
+package yandex_direct_sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// expiryDelta запас времени, за который токен считается истекшим до фактического срока.
+	expiryDelta = time.Minute
+	// authErrorCode код ошибки авторизации API Директа.
+	authErrorCode = "53"
+	maxPeekBody   = 64 << 10
+	// refreshTimeout ограничивает обновление токена, которое не зависит от отмены контекста отдельного запроса.
+	refreshTimeout = 30 * time.Second
+)
+
+var ErrNoToken = errors.New("token is not set")
+
+// TokenSource источник OAuth-токена для запросов к API.
+type TokenSource interface {
+	Token(ctx context.Context) (Token, error)
+}
+
+// refresher источник, умеющий принудительно обновить токен после ошибки авторизации.
+type refresher interface {
+	RefreshStale(ctx context.Context, stale string) (Token, error)
+}
+
+// Valid сообщает, что токен задан и не истекает в ближайшую минуту.
+func (t Token) Valid() bool {
+	return t.AccessToken != "" && (t.Expiry.IsZero() || time.Now().Add(expiryDelta).Before(t.Expiry))
+}
+
+func (t *Token) setExpiry(now time.Time) {
+	if t.ExpiresIn > 0 {
+		t.Expiry = now.Add(time.Duration(t.ExpiresIn) * time.Second)
+	}
+}
+
+type staticTokenSource struct {
+	token Token
+}
+
+// StaticTokenSource возвращает источник, всегда отдающий один и тот же токен.
+func StaticTokenSource(t Token) TokenSource {
+	return staticTokenSource{token: t}
+}
+
+func (s staticTokenSource) Token(context.Context) (Token, error) {
+	return s.token, nil
+}
+
+// RefreshingTokenSource источник токена, обновляющий его по refresh_token на oauth.yandex.ru.
+// Одновременные обновления объединяются в один запрос.
+type RefreshingTokenSource struct {
+	client    *Client
+	mu        sync.Mutex
+	token     Token
+	inflight  *refreshCall
+	onRefresh []func(Token)
+}
+
+type refreshCall struct {
+	done  chan struct{}
+	token Token
+	err   error
+}
+
+// NewRefreshingTokenSource создает источник токена, который обновляет t через приложение клиента.
+func (c *Client) NewRefreshingTokenSource(t Token) *RefreshingTokenSource {
+	return &RefreshingTokenSource{client: c, token: t}
+}
+
+// OnRefresh добавляет обработчик, вызываемый после каждого успешного обновления токена.
+func (s *RefreshingTokenSource) OnRefresh(fn func(Token)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.onRefresh = append(s.onRefresh, fn)
+}
+
+// Current возвращает текущий токен без обновления.
+func (s *RefreshingTokenSource) Current() Token {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.token
+}
+
+// Token возвращает действующий токен, обновляя его при истечении срока.
+func (s *RefreshingTokenSource) Token(ctx context.Context) (Token, error) {
+	s.mu.Lock()
+	token := s.token
+	s.mu.Unlock()
+
+	if token.Valid() {
+		return token, nil
+	}
+
+	return s.refresh(ctx, token.AccessToken)
+}
+
+// Refresh принудительно обновляет токен.
+func (s *RefreshingTokenSource) Refresh(ctx context.Context) (Token, error) {
+	s.mu.Lock()
+	stale := s.token.AccessToken
+	s.mu.Unlock()
+
+	return s.refresh(ctx, stale)
+}
+
+// RefreshStale обновляет токен, если текущий токен совпадает с отвергнутым сервером stale.
+// Если токен уже обновлен другим запросом, возвращает его без повторного обновления.
+func (s *RefreshingTokenSource) RefreshStale(ctx context.Context, stale string) (Token, error) {
+	return s.refresh(ctx, stale)
+}
+
+func (s *RefreshingTokenSource) refresh(ctx context.Context, stale string) (Token, error) {
+	s.mu.Lock()
+
+	if s.token.AccessToken != stale && s.token.Valid() {
+		token := s.token
+		s.mu.Unlock()
+
+		return token, nil
+	}
+
+	call := s.inflight
+	if call == nil {
+		call = &refreshCall{done: make(chan struct{})}
+		s.inflight = call
+		refreshToken := s.token.RefreshToken
+		s.mu.Unlock()
+
+		go s.run(call, refreshToken)
+	} else {
+		s.mu.Unlock()
+	}
+
+	select {
+	case <-call.done:
+		return call.token, call.err
+	case <-ctx.Done():
+		return Token{}, ctx.Err() //nolint:wrapcheck
+	}
+}
+
+func (s *RefreshingTokenSource) run(call *refreshCall, refreshToken string) {
+	ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+	defer cancel()
+
+	token, err := s.client.RefreshToken(ctx, refreshToken)
+
+	s.mu.Lock()
+	s.inflight = nil
+
+	if err == nil {
+		if token.RefreshToken == "" {
+			token.RefreshToken = refreshToken
+		}
+
+		s.token = token
+	}
+
+	hooks := s.onRefresh
+	s.mu.Unlock()
+
+	call.token, call.err = token, err
+	close(call.done)
+
+	if err == nil {
+		for _, fn := range hooks {
+			fn(token)
+		}
+	}
+}
+
+// RefreshToken получает новый токен по refresh_token.
+func (c *Client) RefreshToken(ctx context.Context, refreshToken string) (Token, error) {
+	if refreshToken == "" {
+		return Token{}, fmt.Errorf("refresh token: %w", ErrNoToken)
+	}
+
+	reqAccessURL := url.URL{
+		Scheme: "https",
+		Host:   oauthHost,
+		Path:   "/token",
+	}
+	param := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+		"client_id":     {c.App.ID},
+		"client_secret": {c.App.Secret},
+	}
+
+	return c.requestToken(ctx, reqAccessURL.String(), param)
+}
+
+// SetTokenSource задает источник токена. Если источник задан, поле Token не используется.
+func (c *Client) SetTokenSource(ts TokenSource) {
+	c.tokenSource = ts
+}
+
+func (c *Client) accessToken(ctx context.Context) (string, error) {
+	if c.tokenSource != nil {
+		token, err := c.tokenSource.Token(ctx)
+		if err != nil {
+			return "", fmt.Errorf("token source: %w", err)
+		}
+
+		return token.AccessToken, nil
+	}
+
+	if c.Token == nil || *c.Token == "" {
+		return "", ErrNoToken
+	}
+
+	return *c.Token, nil
+}
+
+// doAuthorized выполняет запрос и один раз повторяет его с обновленным токеном после ошибки авторизации.
+// При повторе меняется только токен в заголовке Authorization: схема (Bearer или OAuth) и остальные заголовки сохраняются.
+func (c *Client) doAuthorized(req *http.Request) (*http.Response, error) {
+	resp, err := c.doLimited(req)
+	if err != nil {
+		return nil, err
+	}
+
+	replayable := req.GetBody != nil || req.Body == nil || req.Body == http.NoBody
+
+	ref, ok := c.tokenSource.(refresher)
+	if !ok || !replayable || !isAuthError(resp) {
+		return resp, nil
+	}
+
+	resp.Body.Close()
+
+	scheme, stale, _ := strings.Cut(req.Header.Get("Authorization"), " ")
+
+	token, err := ref.RefreshStale(req.Context(), stale)
+	if err != nil {
+		return nil, fmt.Errorf("refresh token after auth error: %w", err)
+	}
+
+	retry := req.Clone(req.Context())
+	retry.Header.Set("Authorization", scheme+" "+token.AccessToken)
+
+	if req.GetBody != nil {
+		retry.Body, err = req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("get body: %w", err)
+		}
+	}
+
+	if info, ok := CallInfoFromContext(retry.Context()); ok {
+		info.Attempt++
+		retry = retry.WithContext(WithCallInfo(retry.Context(), info))
+	}
+
+	return c.doLimited(retry)
+}
+
+// doLimited выполняет запрос к API с учетом ограничителя частоты запросов и учетом баллов.
+func (c *Client) doLimited(req *http.Request) (*http.Response, error) {
+	if c.limiter != nil {
+		if err := c.limiter.Wait(req.Context()); err != nil {
+			return nil, fmt.Errorf("rate limiter: %w", err)
+		}
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.units != nil {
+		c.units.observe(req.Header.Get("Client-Login"), resp.Header)
+	}
+
+	return resp, nil
+}
+
+// isAuthError определяет ошибку авторизации по статусу 401 или коду ошибки 53 в теле ответа. Тело восстанавливается.
+// Статус 403 не означает истекший токен (например, нет доступа к клиенту), поэтому токен по нему не обновляется.
+func isAuthError(resp *http.Response) bool {
+	if resp.StatusCode == http.StatusUnauthorized {
+		return true
+	}
+
+	if !strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		return false
+	}
+
+	head, err := io.ReadAll(io.LimitReader(resp.Body, maxPeekBody))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), resp.Body), resp.Body}
+
+	if err != nil {
+		return false
+	}
+
+	var data apiResponse
+	if json.Unmarshal(head, &data) != nil || data.Error == nil {
+		return false
+	}
+
+	return data.Error.ErrorCode == authErrorCode
+}
@@ -0,0 +1,241 @@
+package yandex_direct_sdk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestIsAuthError(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   bool
+	}{
+		{name: "unauthorized", status: http.StatusUnauthorized, want: true},
+		{name: "forbidden", status: http.StatusForbidden, want: false},
+		{name: "forbidden json", status: http.StatusForbidden, body: `{"error":{"error_code":54,"error_string":"Нет прав"}}`, want: false},
+		{name: "code 53", status: http.StatusOK, body: `{"error":{"error_code":53,"error_string":"Ошибка авторизации"}}`, want: true},
+		{name: "code 53 string", status: http.StatusBadRequest, body: `{"error":{"error_code":"53"}}`, want: true},
+		{name: "other code", status: http.StatusOK, body: `{"error":{"error_code":8000}}`, want: false},
+		{name: "result", status: http.StatusOK, body: `{"result":{}}`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{
+				StatusCode: tt.status,
+				Header:     http.Header{"Content-Type": {"application/json; charset=utf-8"}},
+				Body:       io.NopCloser(strings.NewReader(tt.body)),
+			}
+
+			if got := isAuthError(resp); got != tt.want {
+				t.Errorf("isAuthError() = %v; want %v", got, tt.want)
+			}
+
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != tt.body {
+				t.Errorf("body = %q; want %q", body, tt.body)
+			}
+		})
+	}
+}
+
+// tokenServer выдает токены access-1, access-2, ... на /token и отвечает на остальные запросы статусами api
+// по порядку, повторяя последний.
+type tokenServer struct {
+	mu       sync.Mutex
+	delay    time.Duration
+	refresh  int
+	api      []int
+	bodies   []string
+	bearers  []string
+	requests int
+}
+
+func (s *tokenServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/token" {
+		time.Sleep(s.delay)
+
+		s.mu.Lock()
+		s.refresh++
+		n := s.refresh
+		s.mu.Unlock()
+
+		fmt.Fprintf(w, `{"access_token":"access-%d","expires_in":3600}`, n)
+
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests++
+	s.bodies = append(s.bodies, string(body))
+	s.bearers = append(s.bearers, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+
+	status := s.api[0]
+	if len(s.api) > 1 {
+		s.api = s.api[1:]
+	}
+
+	w.WriteHeader(status)
+}
+
+func TestRefreshingTokenSourceSingleFlight(t *testing.T) {
+	const callers = 20
+
+	srv := &tokenServer{delay: 50 * time.Millisecond}
+	ts := newOAuthClient(t, srv).NewRefreshingTokenSource(Token{
+		AccessToken:  "expired",
+		RefreshToken: "refresh",
+		Expiry:       time.Now().Add(-time.Hour),
+	})
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		tokens = map[string]int{}
+	)
+
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			token, err := ts.Token(context.Background())
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			mu.Lock()
+			tokens[token.AccessToken]++
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+
+	if srv.refresh != 1 {
+		t.Errorf("refresh requests = %d; want 1", srv.refresh)
+	}
+
+	if tokens["access-1"] != callers {
+		t.Errorf("tokens = %v; want all %d callers to get access-1", tokens, callers)
+	}
+
+	if got := ts.Current().RefreshToken; got != "refresh" {
+		t.Errorf("RefreshToken = %q; want the previous refresh token kept", got)
+	}
+}
+
+func TestRefreshStale(t *testing.T) {
+	tests := []struct {
+		name        string
+		stale       string
+		wantToken   string
+		wantRefresh int
+	}{
+		{name: "already rotated", stale: "old", wantToken: "current", wantRefresh: 0},
+		{name: "current token rejected", stale: "current", wantToken: "access-1", wantRefresh: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &tokenServer{}
+			ts := newOAuthClient(t, srv).NewRefreshingTokenSource(Token{
+				AccessToken:  "current",
+				RefreshToken: "refresh",
+				Expiry:       time.Now().Add(time.Hour),
+			})
+
+			token, err := ts.RefreshStale(context.Background(), tt.stale)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if token.AccessToken != tt.wantToken {
+				t.Errorf("AccessToken = %q; want %q", token.AccessToken, tt.wantToken)
+			}
+
+			if srv.refresh != tt.wantRefresh {
+				t.Errorf("refresh requests = %d; want %d", srv.refresh, tt.wantRefresh)
+			}
+		})
+	}
+}
+
+func TestDoAuthorizedRetriesOnce(t *testing.T) {
+	const body = `{"method":"get","params":{}}`
+
+	tests := []struct {
+		name        string
+		api         []int
+		wantStatus  int
+		wantBearers []string
+	}{
+		{
+			name:        "retry after 401",
+			api:         []int{http.StatusUnauthorized, http.StatusOK},
+			wantStatus:  http.StatusOK,
+			wantBearers: []string{"expired", "access-1"},
+		},
+		{
+			name:        "second 401 is returned",
+			api:         []int{http.StatusUnauthorized},
+			wantStatus:  http.StatusUnauthorized,
+			wantBearers: []string{"expired", "access-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &tokenServer{api: tt.api}
+			c := newOAuthClient(t, srv)
+			c.SetTokenSource(c.NewRefreshingTokenSource(Token{AccessToken: "expired", RefreshToken: "refresh"}))
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "https://api.direct.yandex.com/json/v5/clients", strings.NewReader(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := c.buildHeader(req); err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := c.doAuthorized(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d; want %d", resp.StatusCode, tt.wantStatus)
+			}
+
+			if srv.refresh != 1 {
+				t.Errorf("refresh requests = %d; want 1", srv.refresh)
+			}
+
+			if !reflect.DeepEqual(srv.bearers, tt.wantBearers) {
+				t.Errorf("bearers = %v; want %v", srv.bearers, tt.wantBearers)
+			}
+
+			if !reflect.DeepEqual(srv.bodies, []string{body, body}) {
+				t.Errorf("bodies = %q; want the body sent twice", srv.bodies)
+			}
+		})
+	}
+}
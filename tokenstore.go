@@ -0,0 +1,141 @@
+package yandex_direct_sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var ErrTokenNotFound = errors.New("token not found")
+
+// TokenStore хранилище токенов, ключом служит логин.
+type TokenStore interface {
+	Load(ctx context.Context, login string) (Token, error)
+	Save(ctx context.Context, login string, token Token) error
+}
+
+// TokenLocker блокировка токена логина между процессами на время обновления.
+type TokenLocker interface {
+	Lock(ctx context.Context, login string) (unlock func() error, err error)
+}
+
+// StoredTokenSource источник токена, который читает токен логина из хранилища и сохраняет туда обновленный токен.
+// Если хранилище реализует TokenLocker, обновление выполняется под блокировкой, и процессы, использующие
+// одно хранилище, не обновляют токен параллельно. Одновременные обновления в процессе объединяются в одно.
+type StoredTokenSource struct {
+	client   *Client
+	store    TokenStore
+	login    string
+	mu       sync.Mutex
+	token    Token
+	inflight *refreshCall
+}
+
+// NewStoredTokenSource создает источник токена логина клиента на основе хранилища.
+func (c *Client) NewStoredTokenSource(store TokenStore) *StoredTokenSource {
+	return &StoredTokenSource{client: c, store: store, login: c.Login}
+}
+
+func (s *StoredTokenSource) Token(ctx context.Context) (Token, error) {
+	s.mu.Lock()
+	token := s.token
+	s.mu.Unlock()
+
+	if token.Valid() {
+		return token, nil
+	}
+
+	return s.RefreshStale(ctx, token.AccessToken)
+}
+
+func (s *StoredTokenSource) RefreshStale(ctx context.Context, stale string) (Token, error) {
+	s.mu.Lock()
+
+	if s.token.AccessToken != stale && s.token.Valid() {
+		token := s.token
+		s.mu.Unlock()
+
+		return token, nil
+	}
+
+	call := s.inflight
+	if call == nil {
+		call = &refreshCall{done: make(chan struct{})}
+		s.inflight = call
+		s.mu.Unlock()
+
+		go s.run(call, stale)
+	} else {
+		s.mu.Unlock()
+	}
+
+	select {
+	case <-call.done:
+		return call.token, call.err
+	case <-ctx.Done():
+		return Token{}, ctx.Err() //nolint:wrapcheck
+	}
+}
+
+func (s *StoredTokenSource) run(call *refreshCall, stale string) {
+	ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+	defer cancel()
+
+	token, err := s.refresh(ctx, stale)
+
+	s.mu.Lock()
+	s.inflight = nil
+
+	if err == nil {
+		s.token = token
+	}
+	s.mu.Unlock()
+
+	call.token, call.err = token, err
+	close(call.done)
+}
+
+// refresh возвращает токен из хранилища, если его уже обновил другой процесс, иначе обновляет и сохраняет его.
+func (s *StoredTokenSource) refresh(ctx context.Context, stale string) (Token, error) {
+	token, err := s.store.Load(ctx, s.login)
+	if err != nil {
+		return Token{}, fmt.Errorf("load token for %s: %w", s.login, err)
+	}
+
+	if token.AccessToken != stale && token.Valid() {
+		return token, nil
+	}
+
+	if locker, ok := s.store.(TokenLocker); ok {
+		unlock, err := locker.Lock(ctx, s.login)
+		if err != nil {
+			return Token{}, fmt.Errorf("lock token for %s: %w", s.login, err)
+		}
+		defer unlock() //nolint:errcheck
+
+		token, err = s.store.Load(ctx, s.login)
+		if err != nil {
+			return Token{}, fmt.Errorf("reload token for %s: %w", s.login, err)
+		}
+
+		if token.AccessToken != stale && token.Valid() {
+			return token, nil
+		}
+	}
+
+	refreshed, err := s.client.RefreshToken(ctx, token.RefreshToken)
+	if err != nil {
+		return Token{}, fmt.Errorf("refresh token for %s: %w", s.login, err)
+	}
+
+	if refreshed.RefreshToken == "" {
+		refreshed.RefreshToken = token.RefreshToken
+	}
+
+	if err := s.store.Save(ctx, s.login, refreshed); err != nil {
+		return Token{}, fmt.Errorf("save token for %s: %w", s.login, err)
+	}
+
+	return refreshed, nil
+}
@@ -0,0 +1,106 @@
+package tokenstore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	saltSize = 16
+	keySize  = 32
+
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+var (
+	ErrEmptyPassphrase = errors.New("passphrase is empty")
+	ErrCorrupted       = errors.New("encrypted token is corrupted")
+)
+
+// magic заголовок зашифрованного файла с номером версии формата.
+var magic = []byte("YDTK1") //nolint:gochecknoglobals
+
+// encrypt шифрует данные AES-256-GCM ключом, полученным из пароля через scrypt.
+// Формат: magic | соль | nonce | шифротекст.
+func encrypt(passphrase string, plain []byte) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, fmt.Errorf("salt: %w", err)
+	}
+
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("nonce: %w", err)
+	}
+
+	out := make([]byte, 0, len(magic)+len(salt)+len(nonce)+len(plain)+aead.Overhead())
+	out = append(out, magic...)
+	out = append(out, salt...)
+	out = append(out, nonce...)
+
+	return aead.Seal(out, nonce, plain, magic), nil
+}
+
+func decrypt(passphrase string, data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, magic) || len(data) < len(magic)+saltSize {
+		return nil, ErrCorrupted
+	}
+
+	data = data[len(magic):]
+	salt, data := data[:saltSize], data[saltSize:]
+
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < aead.NonceSize() {
+		return nil, ErrCorrupted
+	}
+
+	nonce, data := data[:aead.NonceSize()], data[aead.NonceSize():]
+
+	plain, err := aead.Open(nil, nonce, data, magic)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupted, err) //nolint:errorlint
+	}
+
+	return plain, nil
+}
+
+func newAEAD(passphrase string, salt []byte) (cipher.AEAD, error) { //nolint:ireturn
+	if passphrase == "" {
+		return nil, ErrEmptyPassphrase
+	}
+
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, keySize)
+	if err != nil {
+		return nil, fmt.Errorf("derive key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("gcm: %w", err)
+	}
+
+	return aead, nil
+}
@@ -0,0 +1,67 @@
+package tokenstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	sdk "github.com/mg-realcom/yandex-direct-sdk"
+)
+
+const DefaultEnvPrefix = "YANDEX_DIRECT_TOKEN_"
+
+var envUnsafeChars = regexp.MustCompile(`[^A-Za-z0-9]`) //nolint:gochecknoglobals
+
+// Env читает токены из переменных окружения вида <prefix><LOGIN>, где в логине все символы,
+// кроме букв и цифр, заменены на «_». Значение — JSON токена или сам access_token.
+// Save меняет переменную только в текущем процессе.
+type Env struct {
+	prefix string
+}
+
+// NewEnv создает хранилище в переменных окружения. Пустой prefix заменяется на DefaultEnvPrefix.
+func NewEnv(prefix string) *Env {
+	if prefix == "" {
+		prefix = DefaultEnvPrefix
+	}
+
+	return &Env{prefix: prefix}
+}
+
+func (e *Env) key(login string) string {
+	return e.prefix + strings.ToUpper(envUnsafeChars.ReplaceAllString(login, "_"))
+}
+
+func (e *Env) Load(_ context.Context, login string) (sdk.Token, error) {
+	value, ok := os.LookupEnv(e.key(login))
+	if !ok || value == "" {
+		return sdk.Token{}, sdk.ErrTokenNotFound
+	}
+
+	if !strings.HasPrefix(strings.TrimSpace(value), "{") {
+		return sdk.Token{AccessToken: value}, nil
+	}
+
+	var token sdk.Token
+	if err := json.Unmarshal([]byte(value), &token); err != nil {
+		return sdk.Token{}, fmt.Errorf("unmarshal token from %s: %w", e.key(login), err)
+	}
+
+	return token, nil
+}
+
+func (e *Env) Save(_ context.Context, login string, token sdk.Token) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("marshal token: %w", err)
+	}
+
+	if err := os.Setenv(e.key(login), string(data)); err != nil {
+		return fmt.Errorf("set %s: %w", e.key(login), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package tokenstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	sdk "github.com/mg-realcom/yandex-direct-sdk"
+	"github.com/mg-realcom/yandex-direct-sdk/internal/fsutil"
+)
+
+const (
+	filePerm = 0o600
+	dirPerm  = 0o700
+
+	lockRetry = 100 * time.Millisecond
+)
+
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]`) //nolint:gochecknoglobals
+
+// File хранит токены в каталоге, по файлу на логин, в зашифрованном паролем виде.
+// Реализует sdk.TokenLocker через блокировку файлов рядом с токенами средствами ОС:
+// блокировка снимается автоматически при завершении процесса.
+type File struct {
+	dir        string
+	passphrase string
+}
+
+// NewFile создает файловое хранилище в каталоге dir.
+func NewFile(dir, passphrase string) (*File, error) {
+	if passphrase == "" {
+		return nil, ErrEmptyPassphrase
+	}
+
+	if err := os.MkdirAll(dir, dirPerm); err != nil {
+		return nil, fmt.Errorf("create token dir: %w", err)
+	}
+
+	return &File{dir: dir, passphrase: passphrase}, nil
+}
+
+func (f *File) path(login, ext string) string {
+	return filepath.Join(f.dir, unsafeChars.ReplaceAllString(login, "_")+ext)
+}
+
+func (f *File) Load(_ context.Context, login string) (sdk.Token, error) {
+	data, err := os.ReadFile(f.path(login, ".token"))
+	if errors.Is(err, os.ErrNotExist) {
+		return sdk.Token{}, sdk.ErrTokenNotFound
+	}
+
+	if err != nil {
+		return sdk.Token{}, fmt.Errorf("read token: %w", err)
+	}
+
+	plain, err := decrypt(f.passphrase, data)
+	if err != nil {
+		return sdk.Token{}, err
+	}
+
+	var token sdk.Token
+	if err := json.Unmarshal(plain, &token); err != nil {
+		return sdk.Token{}, fmt.Errorf("unmarshal token: %w", err)
+	}
+
+	return token, nil
+}
+
+func (f *File) Save(_ context.Context, login string, token sdk.Token) error {
+	plain, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("marshal token: %w", err)
+	}
+
+	data, err := encrypt(f.passphrase, plain)
+	if err != nil {
+		return err
+	}
+
+	if err := fsutil.WriteFile(f.path(login, ".token"), ".token_*", data, filePerm); err != nil {
+		return fmt.Errorf("save token: %w", err)
+	}
+
+	return nil
+}
+
+// Lock захватывает блокировку логина, ожидая ее освобождения другим процессом до отмены ctx.
+func (f *File) Lock(ctx context.Context, login string) (func() error, error) {
+	lock, err := os.OpenFile(f.path(login, ".lock"), os.O_CREATE|os.O_RDWR, filePerm)
+	if err != nil {
+		return nil, fmt.Errorf("open lock: %w", err)
+	}
+
+	for {
+		ok, err := tryLock(lock)
+		if err != nil {
+			lock.Close()
+
+			return nil, fmt.Errorf("lock: %w", err)
+		}
+
+		if ok {
+			return func() error {
+				unlockErr := unlock(lock)
+				if err := lock.Close(); err != nil && unlockErr == nil {
+					return fmt.Errorf("close lock: %w", err)
+				}
+
+				return unlockErr
+			}, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			lock.Close()
+
+			return nil, ctx.Err() //nolint:wrapcheck
+		case <-time.After(lockRetry):
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package tokenstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdk "github.com/mg-realcom/yandex-direct-sdk"
+)
+
+func TestFileSaveLoad(t *testing.T) {
+	f, err := NewFile(t.TempDir(), "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	if _, err := f.Load(ctx, "user"); !errors.Is(err, sdk.ErrTokenNotFound) {
+		t.Fatalf("Load() error = %v; want ErrTokenNotFound", err)
+	}
+
+	want := sdk.Token{AccessToken: "access", RefreshToken: "refresh", Expiry: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)}
+	if err := f.Save(ctx, "user", want); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := f.Load(ctx, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.AccessToken != want.AccessToken || got.RefreshToken != want.RefreshToken || !got.Expiry.Equal(want.Expiry) {
+		t.Errorf("Load() = %+v; want %+v", got, want)
+	}
+}
+
+func TestFileLock(t *testing.T) {
+	f, err := NewFile(t.TempDir(), "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unlock, err := f.Lock(context.Background(), "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*lockRetry)
+	defer cancel()
+
+	if _, err := f.Lock(ctx, "user"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("second Lock() error = %v; want DeadlineExceeded", err)
+	}
+
+	if err := unlock(); err != nil {
+		t.Fatal(err)
+	}
+
+	unlock, err = f.Lock(context.Background(), "user")
+	if err != nil {
+		t.Fatalf("Lock() after unlock error = %v", err)
+	}
+
+	if err := unlock(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,168 @@
+package tokenstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/storage"
+	sdk "github.com/mg-realcom/yandex-direct-sdk"
+	"google.golang.org/api/googleapi"
+)
+
+// lockStale возраст объекта блокировки, после которого блокировка считается брошенной упавшим процессом.
+const lockStale = 2 * time.Minute
+
+// GCS хранит токены объектами <prefix><login>.token в бакете Google Cloud Storage.
+// Запись выполняется с проверкой поколения объекта, поэтому параллельное сохранение из разных
+// процессов не затирает более свежий токен. Блокировка реализована объектом <prefix><login>.lock.
+// Если задан пароль, токен шифруется так же, как в File.
+type GCS struct {
+	bucket     *storage.BucketHandle
+	prefix     string
+	passphrase string
+
+	mu          sync.Mutex
+	generations map[string]int64
+}
+
+// NewGCS создает хранилище в бакете. Пустой passphrase отключает шифрование на стороне клиента.
+func NewGCS(bucket *storage.BucketHandle, prefix, passphrase string) *GCS {
+	return &GCS{
+		bucket:      bucket,
+		prefix:      prefix,
+		passphrase:  passphrase,
+		generations: map[string]int64{},
+	}
+}
+
+func (g *GCS) object(login, ext string) *storage.ObjectHandle {
+	return g.bucket.Object(g.prefix + unsafeChars.ReplaceAllString(login, "_") + ext)
+}
+
+func (g *GCS) Load(ctx context.Context, login string) (sdk.Token, error) {
+	r, err := g.object(login, ".token").NewReader(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		g.setGeneration(login, 0)
+
+		return sdk.Token{}, sdk.ErrTokenNotFound
+	}
+
+	if err != nil {
+		return sdk.Token{}, fmt.Errorf("open token object: %w", err)
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return sdk.Token{}, fmt.Errorf("read token object: %w", err)
+	}
+
+	if g.passphrase != "" {
+		data, err = decrypt(g.passphrase, data)
+		if err != nil {
+			return sdk.Token{}, err
+		}
+	}
+
+	var token sdk.Token
+	if err := json.Unmarshal(data, &token); err != nil {
+		return sdk.Token{}, fmt.Errorf("unmarshal token: %w", err)
+	}
+
+	g.setGeneration(login, r.Attrs.Generation)
+
+	return token, nil
+}
+
+// Save сохраняет токен, если объект не изменился с момента последнего Load.
+func (g *GCS) Save(ctx context.Context, login string, token sdk.Token) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("marshal token: %w", err)
+	}
+
+	if g.passphrase != "" {
+		data, err = encrypt(g.passphrase, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	obj := g.object(login, ".token")
+
+	g.mu.Lock()
+	generation, known := g.generations[login]
+	g.mu.Unlock()
+
+	if known {
+		if generation == 0 {
+			obj = obj.If(storage.Conditions{DoesNotExist: true})
+		} else {
+			obj = obj.If(storage.Conditions{GenerationMatch: generation})
+		}
+	}
+
+	w := obj.NewWriter(ctx)
+	w.ContentType = "application/octet-stream"
+
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+
+		return fmt.Errorf("write token object: %w", err)
+	}
+
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("close token object: %w", err)
+	}
+
+	g.setGeneration(login, w.Attrs().Generation)
+
+	return nil
+}
+
+// Lock создает объект блокировки, ожидая его удаления другим процессом до отмены ctx.
+func (g *GCS) Lock(ctx context.Context, login string) (func() error, error) {
+	obj := g.object(login, ".lock")
+
+	for {
+		w := obj.If(storage.Conditions{DoesNotExist: true}).NewWriter(ctx)
+		_, _ = w.Write([]byte(time.Now().UTC().Format(time.RFC3339)))
+
+		err := w.Close()
+		if err == nil {
+			return func() error {
+				return obj.Delete(context.Background()) //nolint:contextcheck,wrapcheck
+			}, nil
+		}
+
+		var apiErr *googleapi.Error
+		if !errors.As(err, &apiErr) || apiErr.Code != http.StatusPreconditionFailed {
+			return nil, fmt.Errorf("create lock object: %w", err)
+		}
+
+		if attrs, err := obj.Attrs(ctx); err == nil && time.Since(attrs.Created) > lockStale {
+			_ = obj.If(storage.Conditions{GenerationMatch: attrs.Generation}).Delete(ctx)
+
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err() //nolint:wrapcheck
+		case <-time.After(lockRetry):
+		}
+	}
+}
+
+func (g *GCS) setGeneration(login string, generation int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.generations[login] = generation
+}
@@ -0,0 +1,37 @@
+//go:build windows
+
+package tokenstore
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// tryLock пытается захватить эксклюзивную блокировку файла без ожидания.
+func tryLock(f *os.File) (bool, error) {
+	var ol windows.Overlapped
+
+	err := windows.LockFileEx(windows.Handle(f.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY, 0, 1, 0, &ol)
+	if err == nil {
+		return true, nil
+	}
+
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return false, nil
+	}
+
+	return false, fmt.Errorf("lock file: %w", err)
+}
+
+func unlock(f *os.File) error {
+	var ol windows.Overlapped
+
+	if err := windows.UnlockFileEx(windows.Handle(f.Fd()), 0, 1, 0, &ol); err != nil {
+		return fmt.Errorf("unlock: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package yandex_direct_sdk
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryStore хранит токены в памяти и считает сохранения.
+type memoryStore struct {
+	mu     sync.Mutex
+	tokens map[string]Token
+	saves  int
+}
+
+func (m *memoryStore) Load(_ context.Context, login string) (Token, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	token, ok := m.tokens[login]
+	if !ok {
+		return Token{}, ErrTokenNotFound
+	}
+
+	return token, nil
+}
+
+func (m *memoryStore) Save(_ context.Context, login string, token Token) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.tokens[login] = token
+	m.saves++
+
+	return nil
+}
+
+func expiredStore() *memoryStore {
+	return &memoryStore{tokens: map[string]Token{
+		"client": {AccessToken: "expired", RefreshToken: "refresh", Expiry: time.Now().Add(-time.Hour)},
+	}}
+}
+
+func TestStoredTokenSourceSingleFlight(t *testing.T) {
+	const callers = 20
+
+	srv := &tokenServer{delay: 50 * time.Millisecond}
+	store := expiredStore()
+	ts := newOAuthClient(t, srv).NewStoredTokenSource(store)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			token, err := ts.Token(context.Background())
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			if token.AccessToken != "access-1" {
+				t.Errorf("AccessToken = %q; want access-1", token.AccessToken)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if srv.refresh != 1 || store.saves != 1 {
+		t.Errorf("refresh requests = %d, saves = %d; want 1 and 1", srv.refresh, store.saves)
+	}
+}
+
+func TestStoredTokenSourceCancelDoesNotWaitForRefresh(t *testing.T) {
+	srv := &tokenServer{delay: 300 * time.Millisecond}
+	ts := newOAuthClient(t, srv).NewStoredTokenSource(expiredStore())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	started := time.Now()
+	if _, err := ts.Token(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v; want %v", err, context.DeadlineExceeded)
+	}
+
+	if elapsed := time.Since(started); elapsed > 200*time.Millisecond {
+		t.Errorf("Token returned after %v; want it to stop waiting on cancellation", elapsed)
+	}
+
+	token, err := ts.Token(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if token.AccessToken != "access-1" || srv.refresh != 1 {
+		t.Errorf("AccessToken = %q after %d refreshes; want access-1 after 1", token.AccessToken, srv.refresh)
+	}
+}
+
+func TestStoredTokenSourceRotatedByAnotherProcess(t *testing.T) {
+	srv := &tokenServer{}
+	store := &memoryStore{tokens: map[string]Token{
+		"client": {AccessToken: "rotated", RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour)},
+	}}
+	ts := newOAuthClient(t, srv).NewStoredTokenSource(store)
+
+	token, err := ts.RefreshStale(context.Background(), "old")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if token.AccessToken != "rotated" || srv.refresh != 0 {
+		t.Errorf("AccessToken = %q after %d refreshes; want rotated without refresh", token.AccessToken, srv.refresh)
+	}
+}